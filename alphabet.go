@@ -16,10 +16,7 @@
 
 package lingua
 
-import (
-	"fmt"
-	"regexp"
-)
+import "unicode"
 
 type alphabet int
 
@@ -29,6 +26,7 @@ const (
 	bengali
 	cyrillic
 	devanagari
+	ethiopic
 	georgian
 	greek
 	gujarati
@@ -37,54 +35,71 @@ const (
 	hangul
 	hebrew
 	hiragana
+	kannada
 	katakana
+	khmer
+	lao
 	latin
+	malayalam
+	myanmar
+	oriya
+	sinhala
 	tamil
 	telugu
+	thaana
 	thai
+	tibetan
 )
 
 func (alphabet alphabet) matches(text string) bool {
-	switch alphabet {
-	case arabic:
-		return arabicChars.MatchString(text)
-	case armenian:
-		return armenianChars.MatchString(text)
-	case bengali:
-		return bengaliChars.MatchString(text)
-	case cyrillic:
-		return cyrillicChars.MatchString(text)
-	case devanagari:
-		return devanagariChars.MatchString(text)
-	case georgian:
-		return georgianChars.MatchString(text)
-	case greek:
-		return greekChars.MatchString(text)
-	case gujarati:
-		return gujaratiChars.MatchString(text)
-	case gurmukhi:
-		return gurmukhiChars.MatchString(text)
-	case han:
-		return hanChars.MatchString(text)
-	case hangul:
-		return hangulChars.MatchString(text)
-	case hebrew:
-		return hebrewChars.MatchString(text)
-	case hiragana:
-		return hiraganaChars.MatchString(text)
-	case katakana:
-		return katakanaChars.MatchString(text)
-	case latin:
-		return latinChars.MatchString(text)
-	case tamil:
-		return tamilChars.MatchString(text)
-	case telugu:
-		return teluguChars.MatchString(text)
-	case thai:
-		return thaiChars.MatchString(text)
-	default:
+	if len(text) == 0 {
+		return false
+	}
+	for _, chr := range text {
+		if !alphabet.matchesRune(chr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (alphabet alphabet) matchesRune(chr rune) bool {
+	if alphabet < 0 || int(alphabet) >= len(alphabetRangeTables) {
 		return false
 	}
+	return unicode.Is(alphabetRangeTables[alphabet], chr)
+}
+
+// alphabetOf returns the alphabet which the given character belongs to.
+// Characters of the Basic Multilingual Plane are looked up in a precomputed
+// table, all others are searched in the Unicode range tables of the alphabets.
+func alphabetOf(chr rune) (alphabet, bool) {
+	if chr >= 0 && int(chr) < len(basicMultilingualPlaneAlphabets) {
+		if entry := basicMultilingualPlaneAlphabets[chr]; entry > 0 {
+			return alphabet(entry - 1), true
+		}
+		return 0, false
+	}
+	for i, table := range alphabetRangeTables {
+		if unicode.Is(table, chr) {
+			return alphabet(i), true
+		}
+	}
+	return 0, false
+}
+
+// alphabetOfWord returns the alphabet which all characters
+// of the given word belong to.
+func alphabetOfWord(word string) (alphabet, bool) {
+	wordAlphabet, isFirstCharacter := alphabet(0), true
+	for _, chr := range word {
+		characterAlphabet, exists := alphabetOf(chr)
+		if !exists || (!isFirstCharacter && characterAlphabet != wordAlphabet) {
+			return 0, false
+		}
+		wordAlphabet, isFirstCharacter = characterAlphabet, false
+	}
+	return wordAlphabet, !isFirstCharacter
 }
 
 func (alphabet alphabet) supportedLanguages() (languages []Language) {
@@ -110,34 +125,71 @@ func allAlphabetsSupportingSingleLanguage() map[alphabet]Language {
 }
 
 func allAlphabets() []alphabet {
-	alphabets := make([]alphabet, thai+1)
-	for i := 0; i <= int(thai); i++ {
+	alphabets := make([]alphabet, tibetan+1)
+	for i := 0; i <= int(tibetan); i++ {
 		alphabets[i] = alphabet(i)
 	}
 	return alphabets
 }
 
-var (
-	arabicChars     = createRegexp("Arabic")
-	armenianChars   = createRegexp("Armenian")
-	bengaliChars    = createRegexp("Bengali")
-	cyrillicChars   = createRegexp("Cyrillic")
-	devanagariChars = createRegexp("Devanagari")
-	georgianChars   = createRegexp("Georgian")
-	greekChars      = createRegexp("Greek")
-	gujaratiChars   = createRegexp("Gujarati")
-	gurmukhiChars   = createRegexp("Gurmukhi")
-	hanChars        = createRegexp("Han")
-	hangulChars     = createRegexp("Hangul")
-	hebrewChars     = createRegexp("Hebrew")
-	hiraganaChars   = createRegexp("Hiragana")
-	katakanaChars   = createRegexp("Katakana")
-	latinChars      = createRegexp("Latin")
-	tamilChars      = createRegexp("Tamil")
-	teluguChars     = createRegexp("Telugu")
-	thaiChars       = createRegexp("Thai")
-)
-
-func createRegexp(charClass string) *regexp.Regexp {
-	return regexp.MustCompile(fmt.Sprintf("^\\p{%v}+$", charClass))
+// alphabetRangeTables holds the Unicode range table of each alphabet.
+var alphabetRangeTables = [...]*unicode.RangeTable{
+	arabic:     unicode.Arabic,
+	armenian:   unicode.Armenian,
+	bengali:    unicode.Bengali,
+	cyrillic:   unicode.Cyrillic,
+	devanagari: unicode.Devanagari,
+	ethiopic:   unicode.Ethiopic,
+	georgian:   unicode.Georgian,
+	greek:      unicode.Greek,
+	gujarati:   unicode.Gujarati,
+	gurmukhi:   unicode.Gurmukhi,
+	han:        unicode.Han,
+	hangul:     unicode.Hangul,
+	hebrew:     unicode.Hebrew,
+	hiragana:   unicode.Hiragana,
+	kannada:    unicode.Kannada,
+	katakana:   unicode.Katakana,
+	khmer:      unicode.Khmer,
+	lao:        unicode.Lao,
+	latin:      unicode.Latin,
+	malayalam:  unicode.Malayalam,
+	myanmar:    unicode.Myanmar,
+	oriya:      unicode.Oriya,
+	sinhala:    unicode.Sinhala,
+	tamil:      unicode.Tamil,
+	telugu:     unicode.Telugu,
+	thaana:     unicode.Thaana,
+	thai:       unicode.Thai,
+	tibetan:    unicode.Tibetan,
 }
+
+// alphabetsOfLanguages holds the alphabets of each language
+// so that they do not need to be allocated for every lookup.
+var alphabetsOfLanguages = func() [][]alphabet {
+	alphabets := make([][]alphabet, amountOfSupportedLanguages())
+	for _, language := range AllLanguages() {
+		alphabets[language] = language.alphabets()
+	}
+	return alphabets
+}()
+
+// basicMultilingualPlaneAlphabets maps each character of the Basic
+// Multilingual Plane to its alphabet plus one, or to zero if the character
+// does not belong to any alphabet.
+var basicMultilingualPlaneAlphabets = func() *[0x10000]uint8 {
+	var alphabets [0x10000]uint8
+	for i, table := range alphabetRangeTables {
+		for _, r := range table.R16 {
+			for chr := int(r.Lo); chr <= int(r.Hi); chr += int(r.Stride) {
+				alphabets[chr] = uint8(i + 1)
+			}
+		}
+		for _, r := range table.R32 {
+			for chr := int(r.Lo); chr <= int(r.Hi) && chr < len(alphabets); chr += int(r.Stride) {
+				alphabets[chr] = uint8(i + 1)
+			}
+		}
+	}
+	return &alphabets
+}()
@@ -0,0 +1,62 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"unicode"
+)
+
+func TestAlphabetOfAgreesWithRangeTables(t *testing.T) {
+	for chr := rune(0); chr <= unicode.MaxRune; chr++ {
+		expectedAlphabet, isExpected := alphabet(0), false
+		for i, table := range alphabetRangeTables {
+			if unicode.Is(table, chr) {
+				expectedAlphabet, isExpected = alphabet(i), true
+				break
+			}
+		}
+		actualAlphabet, exists := alphabetOf(chr)
+		if exists != isExpected || actualAlphabet != expectedAlphabet {
+			t.Fatalf("expected alphabet %v (%v) for character %U, got %v (%v)",
+				expectedAlphabet, isExpected, chr, actualAlphabet, exists)
+		}
+	}
+}
+
+func TestAlphabetOfWord(t *testing.T) {
+	testCases := []struct {
+		word             string
+		expectedAlphabet alphabet
+		expectedExists   bool
+	}{
+		{"languages", latin, true},
+		{"язык", cyrillic, true},
+		{"𠀀𠀁", han, true},
+		{"ภาษาไทย", thai, true},
+		{"languaжe", 0, false},
+		{"123", 0, false},
+		{"", 0, false},
+	}
+	for _, testCase := range testCases {
+		alphabet, exists := alphabetOfWord(testCase.word)
+		assert.Equal(t, testCase.expectedExists, exists, "word: %s", testCase.word)
+		assert.Equal(t, testCase.expectedAlphabet, alphabet, "word: %s", testCase.word)
+		assert.Equal(t, exists, alphabet.matches(testCase.word), "word: %s", testCase.word)
+	}
+}
@@ -16,6 +16,13 @@
 
 package lingua
 
+import (
+	"fmt"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"math"
+)
+
 const missingLanguageMessage = "LanguageDetector needs at least 2 languages to choose from"
 
 // UnconfiguredLanguageDetectorBuilder is the interface describing the methods
@@ -96,6 +103,38 @@ type LanguageDetectorBuilder interface {
 	// Panics if distance is smaller than 0.0 or greater than 0.99.
 	WithMinimumRelativeDistance(distance float64) LanguageDetectorBuilder
 
+	// WithMinimumRelativeDistanceCurve sets the minimum relative distance
+	// depending on the number of characters of the input text.
+	//
+	// As the distance between the language probabilities grows with the
+	// length of the input text, a single value is either too strict for short
+	// texts or too lenient for long ones. The given points map character counts
+	// to distances. In between them, the distance is interpolated linearly.
+	// Below the first point and above the last one, the distance of the
+	// respective point is used. This replaces any distance set with
+	// WithMinimumRelativeDistance and vice versa.
+	//
+	// Panics if no points are given, if any distance is smaller than 0.0 or
+	// greater than 0.99 or if the character counts are negative or not
+	// strictly increasing.
+	WithMinimumRelativeDistanceCurve(points ...RelativeDistancePoint) LanguageDetectorBuilder
+
+	// WithLanguageMinimumRelativeDistanceCurve sets a minimum relative distance
+	// curve for the given language which overrides the general distance.
+	//
+	// This allows to require a larger margin for languages which are easily
+	// confused with each other, such as Bosnian, Croatian and Serbian. The curve
+	// of a language applies if it is the most likely or the second most likely
+	// language. If both of them have a curve, the larger distance is used.
+	// The points are interpreted like those passed to
+	// WithMinimumRelativeDistanceCurve. Calling this method again for the same
+	// language replaces its curve.
+	//
+	// Panics if the language is not among the languages of the detector or
+	// if the points are invalid as described for
+	// WithMinimumRelativeDistanceCurve.
+	WithLanguageMinimumRelativeDistanceCurve(language Language, points ...RelativeDistancePoint) LanguageDetectorBuilder
+
 	// WithPreloadedLanguageModels configures LanguageDetectorBuilder to
 	// preload all language models when creating the instance of LanguageDetector.
 	//
@@ -105,8 +144,57 @@ type LanguageDetectorBuilder interface {
 	// beneficial to preload all language models into memory to avoid
 	// unexpected latency while waiting for the service response. This
 	// method allows to switch between these two loading modes.
+	//
+	// If a language model cannot be loaded, for instance because it is
+	// corrupt, Build panics with the *LanguageModelError. Combine this method
+	// with WithBackgroundPreloading to receive the error from
+	// LanguageDetector.WaitUntilReady instead, or call VerifyLanguageModels
+	// before building the LanguageDetector.
 	WithPreloadedLanguageModels() LanguageDetectorBuilder
 
+	// WithBackgroundPreloading configures LanguageDetectorBuilder to preload
+	// all language models in the background after the instance of
+	// LanguageDetector has been created.
+	//
+	// In contrast to WithPreloadedLanguageModels, the creation of the
+	// LanguageDetector does not block until all language models have been
+	// loaded. The detector can be used right away, with language models that
+	// have not been loaded yet being loaded on demand. Use the methods
+	// LanguageDetector.IsReady and LanguageDetector.WaitUntilReady to find
+	// out when all language models are available, for instance in order to
+	// implement the readiness probe of a web service.
+	WithBackgroundPreloading() LanguageDetectorBuilder
+
+	// WithPreloadedLanguages configures LanguageDetectorBuilder to preload
+	// the language models of the given languages only. The language models
+	// of all other languages are loaded on demand.
+	//
+	// This is useful if only a few of the detector's languages occur
+	// frequently in the input texts. The language models of those languages
+	// are then available right away while the rarely needed ones do not take
+	// up memory until they are actually used. Preloading happens when the
+	// LanguageDetector is created or, if WithBackgroundPreloading has been
+	// called, in the background.
+	//
+	// Panics if any of the given languages is not among the languages the
+	// LanguageDetector is built from.
+	WithPreloadedLanguages(languages ...Language) LanguageDetectorBuilder
+
+	// WithPreloadedNgramLengths configures LanguageDetectorBuilder to preload
+	// the language models of the given ngram lengths only. The language models
+	// of all other ngram lengths are loaded on demand.
+	//
+	// By default, only the trigram models are used for texts consisting of
+	// 120 characters or more, so preloading them is sufficient if most of the
+	// input texts are that long. If WithNgramLengths changes the ngram lengths
+	// or the threshold for long texts, the ngram lengths for long texts need
+	// to be preloaded instead. It can be combined with WithPreloadedLanguages to restrict
+	// preloading to specific combinations of languages and ngram lengths.
+	// In low accuracy mode, only trigram models are ever preloaded.
+	//
+	// Panics if any of the given ngram lengths is not in the range 1..5.
+	WithPreloadedNgramLengths(ngramLengths ...int) LanguageDetectorBuilder
+
 	// WithLowAccuracyMode disables the high accuracy mode in order to save
 	// memory and increase performance.
 	//
@@ -121,17 +209,319 @@ type LanguageDetectorBuilder interface {
 	// which are longer than 120 characters will remain mostly unaffected.
 	WithLowAccuracyMode() LanguageDetectorBuilder
 
+	// WithNgramLengths configures LanguageDetectorBuilder to evaluate the
+	// language models of the given ngram lengths for short and long texts.
+	//
+	// By default, the language models of all ngram lengths from 1 to 5 are
+	// evaluated for texts of less than 120 characters, and only the trigram
+	// models for longer texts. Fewer ngram lengths speed up language detection
+	// and need less memory at the cost of accuracy. If the language models are
+	// preloaded or indexed, only the models of the given ngram lengths are
+	// loaded unless WithPreloadedNgramLengths says otherwise. In low accuracy
+	// mode, only trigrams are used regardless of this setting. As unigrams are
+	// needed to average the probabilities over the characters of the text,
+	// leaving them out makes the probabilities of longer texts dominate.
+	//
+	// Panics if the ngram lengths for short or long texts are empty, contain
+	// duplicates or values outside the range 1..5, or if the long text
+	// threshold is not greater than 0.
+	WithNgramLengths(lengths NgramLengths) LanguageDetectorBuilder
+
+	// WithInvertedNgramIndex configures LanguageDetectorBuilder to build an
+	// inverted index which maps each ngram to its probabilities in all
+	// languages of the LanguageDetector.
+	//
+	// By default, the probability of each ngram of the input text is looked up
+	// separately in the language model of each language in question. With the
+	// inverted index, a single lookup per ngram is sufficient to score all
+	// languages at once. This considerably increases the detection speed of
+	// detectors built from many languages. The downside is that all language
+	// models have to be preloaded and the index needs additional memory.
+	//
+	// This method implies WithPreloadedLanguageModels. If WithBackgroundPreloading
+	// is called as well, the index is built in the background and used as soon
+	// as the LanguageDetector is ready.
+	WithInvertedNgramIndex() LanguageDetectorBuilder
+
+	// WithQuantizedLanguageModels configures LanguageDetectorBuilder to
+	// quantize the probabilities of the language models to the given number
+	// of bits when they are loaded into memory.
+	//
+	// By default, the ngrams of a language model are held in a map together
+	// with their probabilities as 64-bit floats. Quantized language models
+	// store their ngrams sorted in a single string instead and each ngram only
+	// needs the index of its probability in a table of levels. If a language
+	// model has more distinct probabilities than 2^bits, its logarithmized
+	// probabilities are rounded to 2^bits evenly spaced levels, which causes a
+	// slight loss in detection accuracy. Otherwise, the probabilities are kept
+	// exactly.
+	//
+	// With all languages preloaded, the memory profiler in the cmd directory
+	// measures 1,023 MB of heap for the full precision language models,
+	// 285 MB for 16 bits and 219 MB for 8 bits. As ngrams are looked up by
+	// binary search instead of by hashing, detection takes about three times
+	// as long. This is useful for systems running low on memory where the low
+	// accuracy mode is not an option. On the test data, 16 bits yield the same
+	// accuracy as full precision and 8 bits lose at most 0.02 percentage
+	// points of mean accuracy, as reported by the accuracy comparison tool in
+	// the cmd directory.
+	//
+	// Panics if bits is neither 8 nor 16.
+	WithQuantizedLanguageModels(bits int) LanguageDetectorBuilder
+
+	// WithHooks configures LanguageDetectorBuilder to invoke the callbacks of
+	// the given LanguageDetectorHooks whenever a language model is loaded into
+	// or removed from memory and whenever a language is detected.
+	//
+	// This allows to monitor how often and how long lazy loading of language
+	// models happens in production and to feed the durations and results of
+	// language detection into metrics and tracing systems. The hooks are also
+	// invoked for language models loaded during preloading.
+	//
+	// Panics if hooks is nil.
+	WithHooks(hooks LanguageDetectorHooks) LanguageDetectorBuilder
+
+	// WithRules registers custom rules which are applied to the words of the
+	// input text in addition to the built-in rules.
+	//
+	// The built-in rules identify languages by their alphabets and by
+	// characters which are unique to them. Custom rules allow to inject
+	// further domain knowledge, such as product-specific vocabularies. A rule
+	// either identifies a language unambiguously or narrows down the set of
+	// candidate languages which are then evaluated by the language models.
+	//
+	// The given order determines whether the rules are run before or after
+	// the built-in ones. Rules are run in the order in which they have been
+	// registered. This method can be called multiple times.
+	//
+	// Panics if any of the given rules is nil or if the order is neither
+	// BeforeBuiltInRules nor AfterBuiltInRules.
+	WithRules(order RuleOrder, rules ...Rule) LanguageDetectorBuilder
+
+	// WithCharacterEvidence adds the given entries to the built-in character
+	// evidence tables of the rule engine.
+	//
+	// The rule engine narrows down the set of possible languages based on
+	// characters which only occur in certain languages. The built-in tables
+	// do not cover every case, such as typographic variants or letters used
+	// in loanwords. This method allows to fill such gaps. An entry whose
+	// characters are marked as unique causes texts consisting mostly of
+	// words with these characters to be identified as its language directly.
+	//
+	// Panics if any entry contains no characters or no languages, if unique
+	// characters do not refer to exactly one language, or if any referenced
+	// language is not among the languages of the LanguageDetector.
+	WithCharacterEvidence(table ...CharacterEvidence) LanguageDetectorBuilder
+
+	// WithReplacedCharacterEvidence replaces the built-in character evidence
+	// tables of the rule engine with the given entries.
+	//
+	// This is useful if the built-in tables lead to wrong decisions for
+	// the input texts at hand. Calling this method without any entries
+	// disables the character evidence of the rule engine altogether.
+	// Detection based on alphabets that are used by a single language only
+	// is not affected.
+	//
+	// Panics for the same reasons as WithCharacterEvidence.
+	WithReplacedCharacterEvidence(table ...CharacterEvidence) LanguageDetectorBuilder
+
+	// WithFunctionWords enables an additional rule stage which narrows down
+	// the possible languages of short texts by very frequent function words,
+	// such as articles, pronouns and prepositions.
+	//
+	// Texts consisting of only one or two words are where the language models
+	// are weakest. If at least the share RuleWordShares.Characters, half by
+	// default, of the words of such a text are function words of certain
+	// languages, only these languages and the languages without a list of
+	// function words are considered further. If there is only one of them, it
+	// is detected right away. Texts of at least NgramLengths.LongTextThreshold
+	// characters, 120 by default, are not affected.
+	//
+	// The lists of function words are shipped with the library for 50
+	// languages. There are no lists for Chinese, Ganda, Hindi, Maori, Marathi,
+	// Shona, Somali, Sotho, Tsonga, Tswana, Xhosa, Yoruba and Zulu, so these
+	// languages are neither detected nor ruled out by this stage. Languages
+	// which are identified by their alphabet alone do not need a list.
+	WithFunctionWords() LanguageDetectorBuilder
+
+	// WithSoftRuleEvidence configures LanguageDetectorBuilder to combine
+	// the results of the built-in rules with the language models instead of
+	// letting them decide on their own.
+	//
+	// By default, a language identified by characters unique to it receives a
+	// confidence value of 1.0 and all other languages receive 0.0. Likewise,
+	// the languages which do not contain the characters of the input text are
+	// excluded. A single stray character, such as a German name quoted in an
+	// English sentence, can thereby override an otherwise clear result of the
+	// language models. With soft rule evidence, these languages receive the
+	// given weights instead, which are added to their logarithmized
+	// probabilities. The filtering by alphabets and languages which are
+	// identified by their alphabet but come without language models are
+	// not affected.
+	//
+	// Panics if any of the weights is negative.
+	WithSoftRuleEvidence(weights RuleEvidenceWeights) LanguageDetectorBuilder
+
+	// WithRuleWordShares configures the thresholds of the built-in rules
+	// which are expressed as shares of the number of words in the input text.
+	//
+	// By default, words which do not point to any language prevent the built-in
+	// rules from identifying a language if they make up at least half of the
+	// text, and languages are only kept if their characters occur in at least
+	// half of the words. The latter share applies to the function words of
+	// WithFunctionWords as well. Lower shares make the built-in rules decide more
+	// often, higher shares make them more cautious.
+	//
+	// Panics if any of the shares is not greater than 0.0 and at most 1.0.
+	WithRuleWordShares(shares RuleWordShares) LanguageDetectorBuilder
+
+	// WithCalibration configures LanguageDetectorBuilder to map the confidence
+	// values computed by the language models to calibrated probabilities.
+	//
+	// The confidence values are normalized likelihoods of the languages which
+	// tend to be too low for short texts and too high for long texts. A calibration
+	// which has been fitted with FitCalibration on labeled texts makes them
+	// match the observed share of correct languages more closely. It affects
+	// the values returned by LanguageDetector.ComputeLanguageConfidenceValues
+	// and LanguageDetector.ComputeLanguageConfidence only, the detected
+	// languages remain the same. Confidence values which are determined by
+	// the rule engine are not calibrated.
+	//
+	// Panics if the calibration is nil.
+	WithCalibration(calibration Calibration) LanguageDetectorBuilder
+
+	// WithNgramOrderWeights configures LanguageDetectorBuilder to combine the
+	// summed up logarithmized probabilities of the different ngram lengths
+	// with the given weights.
+	//
+	// By default, the probabilities of all ngram lengths are simply added up.
+	// Weights which have been fitted with FitNgramOrderWeights on labeled
+	// texts let the more reliable ngram lengths count more, either for all
+	// languages alike or for each language separately. They are read from a
+	// file with ReadNgramOrderWeights. Languages identified by the rule
+	// engine are not affected.
+	//
+	// Panics if the weights are nil.
+	WithNgramOrderWeights(weights NgramOrderWeights) LanguageDetectorBuilder
+
+	// WithUnseenNgramProbability configures LanguageDetectorBuilder to penalize
+	// ngrams of the text which are not contained in the language model of a
+	// language, not even as lower order ngrams.
+	//
+	// Without a penalty, such an ngram does not contribute anything to the
+	// summed up logarithmized probabilities of the language, so languages
+	// with sparse language models are not penalized for character sequences
+	// they have never seen. Instead, each such ngram contributes the logarithm
+	// of the given probability, like a fixed floor probability. Languages
+	// whose language models do not contain any ngram of the text are left out
+	// regardless of this setting.
+	//
+	// A probability of 0.01 has turned out to be the most accurate one on the
+	// test data, see the comparison unseen-ngram-penalty. It raises the mean
+	// accuracy over all languages by only 0.10 percentage points, from 86.42%
+	// to 86.52%, whereas the accuracy for Turkish drops by 0.90 percentage
+	// points and by 2.2 percentage points for single Turkish words. As the
+	// penalty also changes the detected languages and confidence values for
+	// existing users, it is disabled by default, which corresponds to a
+	// probability of 1.0.
+	//
+	// Panics if the probability is not greater than 0.0 and at most 1.0.
+	WithUnseenNgramProbability(probability float64) LanguageDetectorBuilder
+
+	// WithScriptVariants configures LanguageDetectorBuilder to detect languages
+	// also in those scripts which only language models of a script variant
+	// exist for. So far, this applies to Serbian in Latin script.
+	//
+	// By default, Serbian is only detected in Cyrillic script, and Serbian texts
+	// in Latin script are mostly classified as Croatian or Bosnian. With this
+	// setting, the language models of the variant SerbianLatin are loaded
+	// together with the Cyrillic ones, and the Latin characters which Serbian
+	// shares with Bosnian and Croatian point to Serbian as well. As the three
+	// languages are very close to each other, this lowers the accuracy for
+	// Bosnian and Croatian considerably, so it should only be enabled if
+	// Serbian texts in Latin script are to be expected.
+	//
+	// The Latin models are transliterated from the Cyrillic ones, as there is no
+	// Serbian training corpus in Latin script. On the test data, they classify
+	// 62.9% of the Serbian sentences in Latin script correctly, while the
+	// accuracy for Croatian sentences drops from 90.4% to 84.0% and for Bosnian
+	// sentences from 40.9% to 27.3%. This is why Serbian in Latin script is not
+	// detected by default yet, unlike Serbian in Cyrillic script. This may change
+	// once the models can be trained on a Latin corpus.
+	WithScriptVariants() LanguageDetectorBuilder
+
+	// WithLanguagePriors configures LanguageDetectorBuilder to weight the
+	// languages by how likely they are a priori, such as their shares of the
+	// texts to be classified.
+	//
+	// By default, all languages are treated as equally likely. The logarithms
+	// of the priors are added to the summed up logarithmized probabilities of
+	// the language models before these are normalized, so frequent languages
+	// win close calls. This is reflected in the results of
+	// LanguageDetector.ComputeLanguageConfidenceValues. The languages which
+	// are not contained in the map share the probability which the given
+	// priors leave equally. Languages identified by the rule engine are
+	// not affected.
+	//
+	// Panics if any of the languages is not among the languages of the
+	// detector, if any of the priors is not greater than 0.0 and at most 1.0,
+	// if the priors sum up to more than 1.0 or if they sum up to 1.0 without
+	// containing all languages of the detector.
+	WithLanguagePriors(priors map[Language]float64) LanguageDetectorBuilder
+
+	// WithOpenSetDetection configures LanguageDetectorBuilder to recognize
+	// texts which are written in none of the languages of the detector.
+	//
+	// By default, the confidence values are normalized over the languages of
+	// the detector, so a text in any other language is confidently assigned
+	// to one of them. With open-set detection, the trigrams of the text are
+	// looked up in the language models before the languages are compared.
+	// If the share of trigrams found in any of the language models or the
+	// log-likelihood per character in the most likely language falls below
+	// the given thresholds, Unknown is returned with the reason
+	// ReasonUnsupportedLanguage. Texts which are identified by the rule
+	// engine and texts without any trigrams are not affected.
+	//
+	// Panics if the log-likelihood threshold is positive or if the
+	// coverage threshold is not between 0.0 and 1.0.
+	WithOpenSetDetection(thresholds OpenSetThresholds) LanguageDetectorBuilder
+
 	// Build creates and returns the configured instance of LanguageDetector.
+	//
+	// Panics if language models are preloaded synchronously and one of them
+	// cannot be loaded.
 	Build() LanguageDetector
 	getLanguages() []Language
 	getMinimumRelativeDistance() float64
 }
 
 type languageDetectorBuilder struct {
-	languages                     []Language
-	minimumRelativeDistance       float64
-	isEveryLanguageModelPreloaded bool
-	isLowAccuracyModeEnabled      bool
+	languages                       []Language
+	minimumRelativeDistance         float64
+	isEveryLanguageModelPreloaded   bool
+	isBackgroundPreloadingEnabled   bool
+	preloadedLanguages              []Language
+	preloadedNgramLengths           []int
+	isLowAccuracyModeEnabled        bool
+	isNgramIndexEnabled             bool
+	quantizationBits                int
+	hooks                           LanguageDetectorHooks
+	rulesBeforeBuiltIns             []Rule
+	rulesAfterBuiltIns              []Rule
+	characterEvidence               []CharacterEvidence
+	isCharacterEvidenceReplaced     bool
+	isFunctionWordStageEnabled      bool
+	ruleEvidenceWeights             *RuleEvidenceWeights
+	ruleWordShares                  *RuleWordShares
+	calibration                     Calibration
+	logLanguagePriors               map[Language]float64
+	openSetThresholds               *OpenSetThresholds
+	minimumRelativeDistanceCurve    []RelativeDistancePoint
+	languageDistanceCurves          map[Language][]RelativeDistancePoint
+	ngramLengths                    *NgramLengths
+	ngramOrderWeights               NgramOrderWeights
+	unseenNgramLogProbability       float64
+	isScriptVariantDetectionEnabled bool
 }
 
 // NewLanguageDetectorBuilder returns a new instance that implements the
@@ -232,6 +622,31 @@ func (builder *languageDetectorBuilder) WithMinimumRelativeDistance(distance flo
 		panic("Minimum relative distance must lie in between 0.0 and 0.99")
 	}
 	builder.minimumRelativeDistance = distance
+	builder.minimumRelativeDistanceCurve = nil
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithMinimumRelativeDistanceCurve(
+	points ...RelativeDistancePoint,
+) LanguageDetectorBuilder {
+	validateRelativeDistanceCurve(points)
+	builder.minimumRelativeDistance = points[0].Distance
+	builder.minimumRelativeDistanceCurve = slices.Clone(points)
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithLanguageMinimumRelativeDistanceCurve(
+	language Language,
+	points ...RelativeDistancePoint,
+) LanguageDetectorBuilder {
+	if !slices.Contains(builder.languages, language) {
+		panic(fmt.Sprintf("Language %v is not among the languages of the detector", language))
+	}
+	validateRelativeDistanceCurve(points)
+	if builder.languageDistanceCurves == nil {
+		builder.languageDistanceCurves = make(map[Language][]RelativeDistancePoint)
+	}
+	builder.languageDistanceCurves[language] = slices.Clone(points)
 	return builder
 }
 
@@ -240,18 +655,252 @@ func (builder *languageDetectorBuilder) WithPreloadedLanguageModels() LanguageDe
 	return builder
 }
 
+func (builder *languageDetectorBuilder) WithBackgroundPreloading() LanguageDetectorBuilder {
+	builder.isBackgroundPreloadingEnabled = true
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithPreloadedLanguages(languages ...Language) LanguageDetectorBuilder {
+	for _, language := range languages {
+		if !slices.Contains(builder.languages, language) {
+			panic(fmt.Sprintf("Language %v cannot be preloaded because the detector is not built from it", language))
+		}
+	}
+	builder.preloadedLanguages = removeDuplicateLanguages(languages)
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithPreloadedNgramLengths(ngramLengths ...int) LanguageDetectorBuilder {
+	for _, ngramLength := range ngramLengths {
+		if ngramLength < 1 || ngramLength > maxNgramLength {
+			panic(fmt.Sprintf("Ngram length %v is not in range 1..%v", ngramLength, maxNgramLength))
+		}
+	}
+	builder.preloadedNgramLengths = ngramLengths
+	return builder
+}
+
 func (builder *languageDetectorBuilder) WithLowAccuracyMode() LanguageDetectorBuilder {
 	builder.isLowAccuracyModeEnabled = true
 	return builder
 }
 
+func (builder *languageDetectorBuilder) WithNgramLengths(lengths NgramLengths) LanguageDetectorBuilder {
+	validateNgramLengths(lengths)
+	builder.ngramLengths = &NgramLengths{
+		Short:             slices.Clone(lengths.Short),
+		Long:              slices.Clone(lengths.Long),
+		LongTextThreshold: lengths.LongTextThreshold,
+	}
+	slices.Sort(builder.ngramLengths.Short)
+	slices.Sort(builder.ngramLengths.Long)
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithInvertedNgramIndex() LanguageDetectorBuilder {
+	builder.isNgramIndexEnabled = true
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithQuantizedLanguageModels(bits int) LanguageDetectorBuilder {
+	if bits != 8 && bits != 16 {
+		panic("Language models can only be quantized to 8 or 16 bits")
+	}
+	builder.quantizationBits = bits
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithHooks(hooks LanguageDetectorHooks) LanguageDetectorBuilder {
+	if hooks == nil {
+		panic("Hooks must not be nil")
+	}
+	builder.hooks = hooks
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithRules(order RuleOrder, rules ...Rule) LanguageDetectorBuilder {
+	for _, rule := range rules {
+		if rule == nil {
+			panic("Rules must not be nil")
+		}
+	}
+	switch order {
+	case BeforeBuiltInRules:
+		builder.rulesBeforeBuiltIns = append(builder.rulesBeforeBuiltIns, rules...)
+	case AfterBuiltInRules:
+		builder.rulesAfterBuiltIns = append(builder.rulesAfterBuiltIns, rules...)
+	default:
+		panic(fmt.Sprintf("Rule order %v is not supported", order))
+	}
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithCharacterEvidence(table ...CharacterEvidence) LanguageDetectorBuilder {
+	validateCharacterEvidence(builder.languages, table)
+	builder.characterEvidence = append(builder.characterEvidence, table...)
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithReplacedCharacterEvidence(table ...CharacterEvidence) LanguageDetectorBuilder {
+	validateCharacterEvidence(builder.languages, table)
+	builder.characterEvidence = slices.Clone(table)
+	builder.isCharacterEvidenceReplaced = true
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithFunctionWords() LanguageDetectorBuilder {
+	builder.isFunctionWordStageEnabled = true
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithSoftRuleEvidence(weights RuleEvidenceWeights) LanguageDetectorBuilder {
+	if weights.Detection < 0 || weights.Filter < 0 {
+		panic("Rule evidence weights must not be negative")
+	}
+	builder.ruleEvidenceWeights = &weights
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithRuleWordShares(shares RuleWordShares) LanguageDetectorBuilder {
+	if shares.Unknown <= 0 || shares.Unknown > 1 || shares.Characters <= 0 || shares.Characters > 1 {
+		panic("Rule word shares must lie in between 0.0 exclusively and 1.0 inclusively")
+	}
+	builder.ruleWordShares = &shares
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithCalibration(calibration Calibration) LanguageDetectorBuilder {
+	if calibration == nil {
+		panic("Calibration must not be nil")
+	}
+	builder.calibration = calibration
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithNgramOrderWeights(weights NgramOrderWeights) LanguageDetectorBuilder {
+	if weights == nil {
+		panic("Ngram order weights must not be nil")
+	}
+	builder.ngramOrderWeights = weights
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithUnseenNgramProbability(probability float64) LanguageDetectorBuilder {
+	if probability <= 0 || probability > 1 {
+		panic("Unseen ngram probability must lie in between 0.0 exclusively and 1.0 inclusively")
+	}
+	builder.unseenNgramLogProbability = math.Log(probability)
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithScriptVariants() LanguageDetectorBuilder {
+	builder.isScriptVariantDetectionEnabled = true
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithLanguagePriors(priors map[Language]float64) LanguageDetectorBuilder {
+	builder.logLanguagePriors = computeLogPriors(builder.languages, priors)
+	return builder
+}
+
+func (builder *languageDetectorBuilder) WithOpenSetDetection(thresholds OpenSetThresholds) LanguageDetectorBuilder {
+	if thresholds.LogLikelihood > 0 {
+		panic("Open-set log-likelihood threshold must not be positive")
+	}
+	if thresholds.Coverage < 0 || thresholds.Coverage > 1 {
+		panic("Open-set coverage threshold must lie in between 0.0 and 1.0")
+	}
+	builder.openSetThresholds = &thresholds
+	return builder
+}
+
 func (builder *languageDetectorBuilder) Build() LanguageDetector {
-	return newLanguageDetector(
+	detector := newLanguageDetector(
 		builder.languages,
 		builder.minimumRelativeDistance,
-		builder.isEveryLanguageModelPreloaded,
+		false,
 		builder.isLowAccuracyModeEnabled,
 	)
+	detector.quantizationBits = builder.quantizationBits
+	detector.hooks = builder.hooks
+	detector.rulesBeforeBuiltIns = slices.Clone(builder.rulesBeforeBuiltIns)
+	detector.rulesAfterBuiltIns = slices.Clone(builder.rulesAfterBuiltIns)
+	detector.calibration = builder.calibration
+	detector.ngramOrderWeights = builder.ngramOrderWeights
+	detector.unseenNgramLogProbability = builder.unseenNgramLogProbability
+	detector.logLanguagePriors = builder.logLanguagePriors
+	detector.minimumRelativeDistanceCurve = slices.Clone(builder.minimumRelativeDistanceCurve)
+	detector.isScriptVariantDetectionEnabled = builder.isScriptVariantDetectionEnabled
+
+	if builder.isScriptVariantDetectionEnabled {
+		detector.characterLookup = detector.collectCharacterLookup()
+	}
+
+	if builder.ngramLengths != nil {
+		detector.ngramLengths = *builder.ngramLengths
+	}
+
+	if builder.languageDistanceCurves != nil {
+		detector.languageDistanceCurves = maps.Clone(builder.languageDistanceCurves)
+	}
+
+	if builder.openSetThresholds != nil {
+		thresholds := *builder.openSetThresholds
+		detector.openSetThresholds = &thresholds
+	}
+
+	if builder.ruleEvidenceWeights != nil {
+		weights := *builder.ruleEvidenceWeights
+		detector.ruleEvidenceWeights = &weights
+	}
+
+	if builder.ruleWordShares != nil {
+		detector.ruleWordShares = *builder.ruleWordShares
+	}
+
+	if builder.isFunctionWordStageEnabled {
+		detector.functionWords = collectFunctionWords(builder.languages)
+	}
+
+	if len(builder.characterEvidence) > 0 || builder.isCharacterEvidenceReplaced {
+		detector.characterEvidence = newCharacterEvidence(
+			builder.languages,
+			builder.characterEvidence,
+			builder.isCharacterEvidenceReplaced,
+		)
+		detector.languagesWithUniqueCharacters = maps.Keys(detector.characterEvidence.uniqueCharacters)
+		slices.Sort(detector.languagesWithUniqueCharacters)
+		detector.characterLookup = detector.collectCharacterLookup()
+	}
+
+	languagesToPreload := builder.languages
+	if len(builder.preloadedLanguages) > 0 && !builder.isNgramIndexEnabled {
+		languagesToPreload = builder.preloadedLanguages
+	}
+	ngramLengthsToPreload := detector.ngramLengths.all()
+	if len(builder.preloadedNgramLengths) > 0 && !builder.isNgramIndexEnabled {
+		ngramLengthsToPreload = builder.preloadedNgramLengths
+	}
+
+	if builder.isNgramIndexEnabled {
+		detector.ngramIndex = &ngramIndex{}
+	}
+
+	if builder.isBackgroundPreloadingEnabled {
+		detector.readiness = newReadiness()
+		go detector.preloadLanguageModelsInBackground(languagesToPreload, ngramLengthsToPreload)
+	} else if builder.isEveryLanguageModelPreloaded ||
+		builder.isNgramIndexEnabled ||
+		len(builder.preloadedLanguages) > 0 ||
+		len(builder.preloadedNgramLengths) > 0 {
+		detector.mustPreloadLanguageModels(languagesToPreload, ngramLengthsToPreload)
+		if builder.isNgramIndexEnabled {
+			if err := detector.buildNgramIndex(detector.ngramLengths.all()); err != nil {
+				panic(err.Error())
+			}
+		}
+	}
+	return detector
 }
 
 func (builder *languageDetectorBuilder) getLanguages() []Language {
@@ -266,7 +915,29 @@ func (builder *languageDetectorBuilder) from(languages []Language) LanguageDetec
 	builder.languages = removeDuplicateLanguages(languages)
 	builder.minimumRelativeDistance = 0.0
 	builder.isEveryLanguageModelPreloaded = false
+	builder.isBackgroundPreloadingEnabled = false
+	builder.preloadedLanguages = nil
+	builder.preloadedNgramLengths = nil
 	builder.isLowAccuracyModeEnabled = false
+	builder.isNgramIndexEnabled = false
+	builder.quantizationBits = 0
+	builder.hooks = nil
+	builder.rulesBeforeBuiltIns = nil
+	builder.rulesAfterBuiltIns = nil
+	builder.characterEvidence = nil
+	builder.isCharacterEvidenceReplaced = false
+	builder.isFunctionWordStageEnabled = false
+	builder.ruleEvidenceWeights = nil
+	builder.ruleWordShares = nil
+	builder.calibration = nil
+	builder.logLanguagePriors = nil
+	builder.openSetThresholds = nil
+	builder.minimumRelativeDistanceCurve = nil
+	builder.languageDistanceCurves = nil
+	builder.ngramLengths = nil
+	builder.ngramOrderWeights = nil
+	builder.unseenNgramLogProbability = 0
+	builder.isScriptVariantDetectionEnabled = false
 	return builder
 }
 
@@ -17,6 +17,7 @@
 package lingua
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -66,6 +67,7 @@ func TestLanguageDetectorBuilder_FromAllLanguagesWithout(t *testing.T) {
 		[]Language{
 			Afrikaans,
 			Albanian,
+			Amharic,
 			Arabic,
 			Armenian,
 			Azerbaijani,
@@ -75,11 +77,13 @@ func TestLanguageDetectorBuilder_FromAllLanguagesWithout(t *testing.T) {
 			Bokmal,
 			Bosnian,
 			Bulgarian,
+			Burmese,
 			Catalan,
 			Chinese,
 			Croatian,
 			Czech,
 			Danish,
+			Dhivehi,
 			Dutch,
 			English,
 			Esperanto,
@@ -99,17 +103,22 @@ func TestLanguageDetectorBuilder_FromAllLanguagesWithout(t *testing.T) {
 			Irish,
 			Italian,
 			Japanese,
+			Kannada,
 			Kazakh,
+			Khmer,
 			Korean,
+			Lao,
 			Latin,
 			Latvian,
 			Lithuanian,
 			Macedonian,
 			Malay,
+			Malayalam,
 			Maori,
 			Marathi,
 			Mongolian,
 			Nynorsk,
+			Odia,
 			Persian,
 			Polish,
 			Portuguese,
@@ -117,6 +126,7 @@ func TestLanguageDetectorBuilder_FromAllLanguagesWithout(t *testing.T) {
 			Russian,
 			Serbian,
 			Shona,
+			Sinhala,
 			Slovak,
 			Slovene,
 			Somali,
@@ -128,6 +138,7 @@ func TestLanguageDetectorBuilder_FromAllLanguagesWithout(t *testing.T) {
 			Tamil,
 			Telugu,
 			Thai,
+			Tibetan,
 			Tsonga,
 			Tswana,
 			Ukrainian,
@@ -290,6 +301,357 @@ func TestLanguageDetectorBuilder_WithMinimumRelativeDistance_Panics_2(t *testing
 	)
 }
 
+func TestLanguageDetectorBuilder_WithQuantizedLanguageModels_Panics(t *testing.T) {
+	assert.PanicsWithValue(
+		t,
+		"Language models can only be quantized to 8 or 16 bits",
+		func() {
+			NewLanguageDetectorBuilder().
+				FromAllLanguages().
+				WithQuantizedLanguageModels(4)
+		},
+	)
+}
+
+func TestLanguageDetectorBuilder_WithPreloadedLanguages_Panics(t *testing.T) {
+	assert.PanicsWithValue(
+		t,
+		"Language French cannot be preloaded because the detector is not built from it",
+		func() {
+			NewLanguageDetectorBuilder().
+				FromLanguages(English, German).
+				WithPreloadedLanguages(German, French)
+		},
+	)
+}
+
+func TestLanguageDetectorBuilder_WithPreloadedNgramLengths_Panics(t *testing.T) {
+	testCases := []int{0, 6}
+	for _, ngramLength := range testCases {
+		assert.PanicsWithValue(
+			t,
+			fmt.Sprintf("Ngram length %v is not in range 1..5", ngramLength),
+			func() {
+				NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithPreloadedNgramLengths(3, ngramLength)
+			},
+		)
+	}
+}
+
+func TestLanguageDetectorBuilder_WithHooks_Panics(t *testing.T) {
+	assert.PanicsWithValue(
+		t,
+		"Hooks must not be nil",
+		func() {
+			NewLanguageDetectorBuilder().
+				FromAllLanguages().
+				WithHooks(nil)
+		},
+	)
+}
+
+func TestLanguageDetectorBuilder_WithRules_Panics(t *testing.T) {
+	assert.PanicsWithValue(
+		t,
+		"Rules must not be nil",
+		func() {
+			NewLanguageDetectorBuilder().
+				FromAllLanguages().
+				WithRules(BeforeBuiltInRules, nil)
+		},
+	)
+	assert.PanicsWithValue(
+		t,
+		"Rule order 2 is not supported",
+		func() {
+			NewLanguageDetectorBuilder().
+				FromAllLanguages().
+				WithRules(RuleOrder(2), candidatesRule(English))
+		},
+	)
+}
+
+func TestLanguageDetectorBuilder_WithSoftRuleEvidence_Panics(t *testing.T) {
+	testCases := []RuleEvidenceWeights{{-1, 1}, {1, -0.5}}
+	for _, weights := range testCases {
+		assert.PanicsWithValue(
+			t,
+			"Rule evidence weights must not be negative",
+			func() {
+				NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithSoftRuleEvidence(weights)
+			},
+		)
+	}
+}
+
+func TestLanguageDetectorBuilder_WithRuleWordShares_Panics(t *testing.T) {
+	testCases := []RuleWordShares{{0, 0.5}, {0.5, 1.1}, {-0.5, 0.5}}
+	for _, shares := range testCases {
+		assert.PanicsWithValue(
+			t,
+			"Rule word shares must lie in between 0.0 exclusively and 1.0 inclusively",
+			func() {
+				NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithRuleWordShares(shares)
+			},
+		)
+	}
+}
+
+func TestLanguageDetectorBuilder_WithCalibration_Panics(t *testing.T) {
+	assert.PanicsWithValue(
+		t,
+		"Calibration must not be nil",
+		func() {
+			NewLanguageDetectorBuilder().
+				FromAllLanguages().
+				WithCalibration(nil)
+		},
+	)
+}
+
+func TestLanguageDetectorBuilder_WithNgramOrderWeights_Panics(t *testing.T) {
+	assert.PanicsWithValue(
+		t,
+		"Ngram order weights must not be nil",
+		func() {
+			NewLanguageDetectorBuilder().
+				FromLanguages(English, German).
+				WithNgramOrderWeights(nil)
+		},
+	)
+}
+
+func TestLanguageDetectorBuilder_WithUnseenNgramProbability_Panics(t *testing.T) {
+	for _, probability := range []float64{0.0, -0.1, 1.1} {
+		assert.PanicsWithValue(
+			t,
+			"Unseen ngram probability must lie in between 0.0 exclusively and 1.0 inclusively",
+			func() {
+				NewLanguageDetectorBuilder().
+					FromLanguages(English, German).
+					WithUnseenNgramProbability(probability)
+			},
+		)
+	}
+}
+
+func TestLanguageDetectorBuilder_WithLanguagePriors_Panics(t *testing.T) {
+	testCases := []struct {
+		priors          map[Language]float64
+		expectedMessage string
+	}{
+		{
+			map[Language]float64{French: 0.5},
+			"Language French of the language priors is not among the languages of the detector",
+		},
+		{
+			map[Language]float64{English: 0},
+			"Language priors must lie in between 0.0 exclusively and 1.0 inclusively",
+		},
+		{
+			map[Language]float64{English: 1.5},
+			"Language priors must lie in between 0.0 exclusively and 1.0 inclusively",
+		},
+		{
+			map[Language]float64{English: 0.7, German: 0.4},
+			"Language priors must not sum up to more than 1.0",
+		},
+		{
+			map[Language]float64{English: 0.7, German: 0.3},
+			"Language priors must leave some probability for the languages without prior",
+		},
+	}
+	for _, testCase := range testCases {
+		assert.PanicsWithValue(
+			t,
+			testCase.expectedMessage,
+			func() {
+				NewLanguageDetectorBuilder().
+					FromLanguages(English, German, Spanish).
+					WithLanguagePriors(testCase.priors)
+			},
+		)
+	}
+}
+
+func TestLanguageDetectorBuilder_WithOpenSetDetection_Panics(t *testing.T) {
+	testCases := []struct {
+		thresholds      OpenSetThresholds
+		expectedMessage string
+	}{
+		{
+			OpenSetThresholds{LogLikelihood: 0.5, Coverage: 0.9},
+			"Open-set log-likelihood threshold must not be positive",
+		},
+		{
+			OpenSetThresholds{LogLikelihood: -3, Coverage: -0.1},
+			"Open-set coverage threshold must lie in between 0.0 and 1.0",
+		},
+		{
+			OpenSetThresholds{LogLikelihood: -3, Coverage: 1.1},
+			"Open-set coverage threshold must lie in between 0.0 and 1.0",
+		},
+	}
+	for _, testCase := range testCases {
+		assert.PanicsWithValue(
+			t,
+			testCase.expectedMessage,
+			func() {
+				NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithOpenSetDetection(testCase.thresholds)
+			},
+		)
+	}
+}
+
+func TestLanguageDetectorBuilder_WithNgramLengths_Panics(t *testing.T) {
+	testCases := []struct {
+		lengths         NgramLengths
+		expectedMessage string
+	}{
+		{
+			NgramLengths{Short: nil, Long: []int{3}, LongTextThreshold: 120},
+			"Ngram lengths for short and long texts must not be empty",
+		},
+		{
+			NgramLengths{Short: []int{1, 2, 3}, Long: []int{}, LongTextThreshold: 120},
+			"Ngram lengths for short and long texts must not be empty",
+		},
+		{
+			NgramLengths{Short: []int{0, 1}, Long: []int{3}, LongTextThreshold: 120},
+			"Ngram length 0 is not in range 1..5",
+		},
+		{
+			NgramLengths{Short: []int{1, 2}, Long: []int{3, 6}, LongTextThreshold: 120},
+			"Ngram length 6 is not in range 1..5",
+		},
+		{
+			NgramLengths{Short: []int{1, 2, 1}, Long: []int{3}, LongTextThreshold: 120},
+			"Ngram length 1 must not be given more than once",
+		},
+		{
+			NgramLengths{Short: []int{1, 2}, Long: []int{3}, LongTextThreshold: 0},
+			"Long text threshold must be greater than 0",
+		},
+	}
+	for _, testCase := range testCases {
+		assert.PanicsWithValue(
+			t,
+			testCase.expectedMessage,
+			func() {
+				NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithNgramLengths(testCase.lengths)
+			},
+		)
+	}
+}
+
+func TestLanguageDetectorBuilder_WithMinimumRelativeDistanceCurve_Panics(t *testing.T) {
+	testCases := []struct {
+		points          []RelativeDistancePoint
+		expectedMessage string
+	}{
+		{
+			nil,
+			"Minimum relative distance curve must contain at least one point",
+		},
+		{
+			[]RelativeDistancePoint{{0, 0.1}, {10, 1.0}},
+			"Minimum relative distance must lie in between 0.0 and 0.99",
+		},
+		{
+			[]RelativeDistancePoint{{-1, 0.1}},
+			"Character counts of the minimum relative distance curve must be non-negative and strictly increasing",
+		},
+		{
+			[]RelativeDistancePoint{{10, 0.1}, {10, 0.2}},
+			"Character counts of the minimum relative distance curve must be non-negative and strictly increasing",
+		},
+	}
+	for _, testCase := range testCases {
+		assert.PanicsWithValue(
+			t,
+			testCase.expectedMessage,
+			func() {
+				NewLanguageDetectorBuilder().
+					FromLanguages(English, German).
+					WithMinimumRelativeDistanceCurve(testCase.points...)
+			},
+		)
+		assert.PanicsWithValue(
+			t,
+			testCase.expectedMessage,
+			func() {
+				NewLanguageDetectorBuilder().
+					FromLanguages(English, German).
+					WithLanguageMinimumRelativeDistanceCurve(German, testCase.points...)
+			},
+		)
+	}
+
+	assert.PanicsWithValue(
+		t,
+		"Language French is not among the languages of the detector",
+		func() {
+			NewLanguageDetectorBuilder().
+				FromLanguages(English, German).
+				WithLanguageMinimumRelativeDistanceCurve(French, RelativeDistancePoint{0, 0.5})
+		},
+	)
+}
+
+func TestLanguageDetectorBuilder_WithCharacterEvidence_Panics(t *testing.T) {
+	testCases := []struct {
+		evidence        CharacterEvidence
+		expectedMessage string
+	}{
+		{
+			CharacterEvidence{"", []Language{English}, false},
+			"Character evidence must contain at least one character",
+		},
+		{
+			CharacterEvidence{"Ææ", nil, false},
+			"Character evidence 'Ææ' must refer to at least one language",
+		},
+		{
+			CharacterEvidence{"Ææ", []Language{English, German}, true},
+			"Characters 'Ææ' can only be unique to exactly one language",
+		},
+		{
+			CharacterEvidence{"Ææ", []Language{English, French}, false},
+			"Language French of character evidence 'Ææ' is not among the languages of the detector",
+		},
+	}
+	for _, testCase := range testCases {
+		assert.PanicsWithValue(
+			t,
+			testCase.expectedMessage,
+			func() {
+				NewLanguageDetectorBuilder().
+					FromLanguages(English, German).
+					WithCharacterEvidence(testCase.evidence)
+			},
+		)
+		assert.PanicsWithValue(
+			t,
+			testCase.expectedMessage,
+			func() {
+				NewLanguageDetectorBuilder().
+					FromLanguages(English, German).
+					WithReplacedCharacterEvidence(testCase.evidence)
+			},
+		)
+	}
+}
+
 func BenchmarkPreloadingAllLanguageModels(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		NewLanguageDetectorBuilder().
@@ -0,0 +1,472 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CalibrationMethod is the type used for enumerating the methods with which
+// a Calibration can be fitted.
+type CalibrationMethod int
+
+const (
+	// TemperatureScaling raises all confidence values to the same power and
+	// normalizes them again. The exponent is fitted such that the likelihood
+	// of the true languages of the labeled texts is maximized. The order of
+	// the languages is preserved.
+	TemperatureScaling CalibrationMethod = iota
+
+	// IsotonicRegression maps each confidence value to the share of correct
+	// languages among the languages of the labeled texts which have received
+	// a similar confidence value, and normalizes the mapped values again.
+	// The mapping is non-decreasing, so the order of the languages is
+	// preserved, but it needs considerably more labeled texts than
+	// TemperatureScaling.
+	IsotonicRegression
+)
+
+// shortTextBucketBoundaries are the smallest character counts of the text
+// length buckets below the long text threshold for which a Calibration is
+// fitted separately.
+var shortTextBucketBoundaries = []int{0, 10, 20, 50}
+
+// isotonicRegressionBins is the number of equally sized bins into which
+// the confidence values are grouped before isotonic regression is applied.
+const isotonicRegressionBins = 1000
+
+// LabeledText is a text whose language is known. Labeled texts are used
+// for fitting a Calibration with FitCalibration.
+type LabeledText struct {
+	Text     string
+	Language Language
+}
+
+// Calibration is the interface describing a mapping of the confidence values
+// computed by LanguageDetector.ComputeLanguageConfidenceValues to calibrated
+// probabilities. A calibrated probability of 0.8 means that the language is
+// correct for about 80% of the texts which receive this probability.
+//
+// A Calibration is fitted separately for several text length buckets by
+// FitCalibration, written with WriteTo and read again with ReadCalibration.
+// It is applied to a detector with LanguageDetectorBuilder.WithCalibration.
+type Calibration interface {
+	// Method returns the method with which the calibration has been fitted.
+	Method() CalibrationMethod
+
+	// WriteTo writes the calibration in JSON format to the given writer.
+	WriteTo(w io.Writer) (int64, error)
+
+	calibrate(values []ConfidenceValue, characterCount int) []ConfidenceValue
+}
+
+type calibration struct {
+	method  CalibrationMethod
+	buckets []calibrationBucket
+}
+
+// calibrationBucket holds the fitted parameters for texts of at least
+// MinimumCharacters characters. Temperature is used by TemperatureScaling,
+// the interpolation points ConfidenceValues and Probabilities are used by
+// IsotonicRegression.
+type calibrationBucket struct {
+	MinimumCharacters int       `json:"minimumCharacters"`
+	Temperature       float64   `json:"temperature,omitempty"`
+	ConfidenceValues  []float64 `json:"confidenceValues,omitempty"`
+	Probabilities     []float64 `json:"probabilities,omitempty"`
+}
+
+type serializableCalibration struct {
+	Method  string              `json:"method"`
+	Buckets []calibrationBucket `json:"buckets"`
+}
+
+// calibrationSample holds the non-zero confidence values computed for a
+// labeled text and the index of its true language among them. The index
+// is -1 if the true language has received a confidence value of 0.0.
+type calibrationSample struct {
+	values    []float64
+	trueIndex int
+}
+
+// FitCalibration fits a Calibration with the given method from the given
+// labeled texts. The confidence values are computed by the given detector,
+// which must have been built by LanguageDetectorBuilder. The calibration
+// should only be applied to detectors with the same configuration.
+//
+// The text length buckets start at 0, 10, 20 and 50 characters, as far as
+// these are shorter than the long text threshold of the detector's
+// NgramLengths, and at the threshold itself.
+//
+// Only texts whose confidence values are computed by the language models
+// are taken into account, as the rule engine decides with certainty. If a
+// text length bucket does not contain any of these texts, the confidence
+// values of this bucket are left unchanged.
+//
+// An error is returned if the method is unknown, if the detector has not
+// been built by LanguageDetectorBuilder or if none of the texts is
+// evaluated by the language models.
+func FitCalibration(detector LanguageDetector, texts []LabeledText, method CalibrationMethod) (Calibration, error) {
+	if method != TemperatureScaling && method != IsotonicRegression {
+		return nil, fmt.Errorf("unknown calibration method %v", method)
+	}
+	builtDetector, ok := detector.(languageDetector)
+	if !ok {
+		return nil, errors.New("detector has not been built by LanguageDetectorBuilder")
+	}
+
+	boundaries := calibrationBucketBoundaries(builtDetector.ngramLengths.LongTextThreshold)
+	samples := make([][]calibrationSample, len(boundaries))
+	sampleCount := 0
+
+	for _, text := range texts {
+		values, reason := builtDetector.computeLanguageConfidenceValues(text.Text)
+		if reason != ReasonNgramModels {
+			continue
+		}
+		sample := calibrationSample{trueIndex: -1}
+		for _, value := range values {
+			if value.Value() == 0 {
+				continue
+			}
+			if value.Language() == text.Language {
+				sample.trueIndex = len(sample.values)
+			}
+			sample.values = append(sample.values, value.Value())
+		}
+		bucket := calibrationBucketOf(boundaries, countCharacters(splitTextIntoWords(text.Text)))
+		samples[bucket] = append(samples[bucket], sample)
+		sampleCount++
+	}
+
+	if sampleCount == 0 {
+		return nil, errors.New("none of the texts has been evaluated by the language models")
+	}
+
+	buckets := make([]calibrationBucket, len(boundaries))
+	for i, minimumCharacters := range boundaries {
+		buckets[i].MinimumCharacters = minimumCharacters
+		if method == TemperatureScaling {
+			buckets[i].Temperature = fitTemperature(samples[i])
+		} else {
+			buckets[i].ConfidenceValues, buckets[i].Probabilities = fitIsotonicRegression(samples[i])
+		}
+	}
+
+	return calibration{method, buckets}, nil
+}
+
+// ReadCalibration reads a Calibration in JSON format as written by
+// Calibration.WriteTo from the given reader.
+//
+// An error is returned if the content cannot be read or does not
+// describe a valid calibration.
+func ReadCalibration(r io.Reader) (Calibration, error) {
+	var serialized serializableCalibration
+	if err := json.NewDecoder(r).Decode(&serialized); err != nil {
+		return nil, err
+	}
+
+	method, err := parseCalibrationMethod(serialized.Method)
+	if err != nil {
+		return nil, err
+	}
+	if len(serialized.Buckets) == 0 {
+		return nil, errors.New("calibration contains no buckets")
+	}
+
+	for i, bucket := range serialized.Buckets {
+		if (i == 0 && bucket.MinimumCharacters != 0) ||
+			(i > 0 && bucket.MinimumCharacters <= serialized.Buckets[i-1].MinimumCharacters) {
+			return nil, fmt.Errorf("bucket %d has an invalid minimum character count", i)
+		}
+		if method == TemperatureScaling && bucket.Temperature <= 0 {
+			return nil, fmt.Errorf("bucket %d has a temperature which is not positive", i)
+		}
+		if method == IsotonicRegression {
+			if len(bucket.ConfidenceValues) != len(bucket.Probabilities) {
+				return nil, fmt.Errorf("bucket %d has a different number of confidence values and probabilities", i)
+			}
+			if !sort.Float64sAreSorted(bucket.ConfidenceValues) || !sort.Float64sAreSorted(bucket.Probabilities) {
+				return nil, fmt.Errorf("bucket %d has interpolation points which are not sorted", i)
+			}
+		}
+	}
+
+	return calibration{method, serialized.Buckets}, nil
+}
+
+// ReadLabeledTexts reads the test data files sentences.txt, single-words.txt
+// and word-pairs.txt which have been written by CreateAndWriteTestDataFiles
+// to the given directory and labels each of their lines with the given
+// language. Files which do not exist are skipped.
+//
+// An error is returned if the directory path is not absolute, if a file
+// cannot be read or if none of the files exists.
+func ReadLabeledTexts(directoryPath string, language Language) ([]LabeledText, error) {
+	if !filepath.IsAbs(directoryPath) {
+		return nil, fmt.Errorf("directory path '%s' is not absolute", directoryPath)
+	}
+
+	var texts []LabeledText
+	fileCount := 0
+
+	for _, fileName := range []string{"sentences.txt", "single-words.txt", "word-pairs.txt"} {
+		content, err := os.ReadFile(filepath.Join(directoryPath, fileName))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		fileCount++
+		for _, line := range strings.Split(string(content), "\n") {
+			if text := strings.TrimSpace(line); len(text) > 0 {
+				texts = append(texts, LabeledText{text, language})
+			}
+		}
+	}
+
+	if fileCount == 0 {
+		return nil, fmt.Errorf("directory '%s' does not contain any test data files", directoryPath)
+	}
+	return texts, nil
+}
+
+func (c calibration) Method() CalibrationMethod {
+	return c.method
+}
+
+func (c calibration) WriteTo(w io.Writer) (int64, error) {
+	content, err := json.MarshalIndent(serializableCalibration{c.method.String(), c.buckets}, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	return bytes.NewReader(append(content, '\n')).WriteTo(w)
+}
+
+// calibrate maps the given confidence values, which are expected to be
+// sorted in descending order, to calibrated probabilities. As both methods
+// preserve the order of the values, the result is sorted as well. Values of
+// 0.0 remain unchanged.
+func (c calibration) calibrate(values []ConfidenceValue, characterCount int) []ConfidenceValue {
+	bucket := c.buckets[0]
+	for _, b := range c.buckets {
+		if characterCount >= b.MinimumCharacters {
+			bucket = b
+		}
+	}
+
+	mappedValues := make([]float64, len(values))
+	for i, value := range values {
+		if value.Value() == 0 {
+			continue
+		}
+		if c.method == TemperatureScaling {
+			mappedValues[i] = math.Pow(value.Value(), 1/bucket.Temperature)
+		} else {
+			mappedValues[i] = bucket.interpolate(value.Value())
+		}
+	}
+
+	sum := 0.0
+	for _, value := range mappedValues {
+		sum += value
+	}
+	if sum == 0 || math.IsInf(sum, 0) || math.IsNaN(sum) {
+		return values
+	}
+
+	calibratedValues := make([]ConfidenceValue, len(values))
+	for i, value := range values {
+		calibratedValues[i] = newConfidenceValue(value.Language(), mappedValues[i]/sum)
+	}
+	return calibratedValues
+}
+
+// interpolate maps the given confidence value linearly between the
+// interpolation points of isotonic regression. Below the first point,
+// it is interpolated towards the origin, above the last point, the
+// probability of the last point is returned.
+func (bucket calibrationBucket) interpolate(value float64) float64 {
+	points := len(bucket.ConfidenceValues)
+	if points == 0 {
+		return value
+	}
+	i := sort.SearchFloat64s(bucket.ConfidenceValues, value)
+	if i == points {
+		return bucket.Probabilities[points-1]
+	}
+	lowerValue, lowerProbability := 0.0, 0.0
+	if i > 0 {
+		lowerValue, lowerProbability = bucket.ConfidenceValues[i-1], bucket.Probabilities[i-1]
+	}
+	upperValue, upperProbability := bucket.ConfidenceValues[i], bucket.Probabilities[i]
+	if upperValue == lowerValue {
+		return upperProbability
+	}
+	return lowerProbability + (value-lowerValue)/(upperValue-lowerValue)*(upperProbability-lowerProbability)
+}
+
+// fitTemperature returns the temperature which minimizes the negative
+// log-likelihood of the true languages. Samples whose true language has
+// received a confidence value of 0.0 cannot be improved by any temperature
+// and are therefore ignored. As the negative log-likelihood is convex in
+// the inverse temperature, golden-section search finds its minimum.
+func fitTemperature(samples []calibrationSample) float64 {
+	var logValues [][]float64
+	var trueIndices []int
+	for _, sample := range samples {
+		if sample.trueIndex < 0 {
+			continue
+		}
+		sampleLogValues := make([]float64, len(sample.values))
+		for i, value := range sample.values {
+			sampleLogValues[i] = math.Log(value)
+		}
+		logValues = append(logValues, sampleLogValues)
+		trueIndices = append(trueIndices, sample.trueIndex)
+	}
+	if len(logValues) == 0 {
+		return 1
+	}
+
+	negativeLogLikelihood := func(logTemperature float64) float64 {
+		inverseTemperature := math.Exp(-logTemperature)
+		sum := 0.0
+		for i, sampleLogValues := range logValues {
+			maximum := math.Inf(-1)
+			for _, logValue := range sampleLogValues {
+				maximum = math.Max(maximum, inverseTemperature*logValue)
+			}
+			exponentialSum := 0.0
+			for _, logValue := range sampleLogValues {
+				exponentialSum += math.Exp(inverseTemperature*logValue - maximum)
+			}
+			sum += maximum + math.Log(exponentialSum) - inverseTemperature*sampleLogValues[trueIndices[i]]
+		}
+		return sum
+	}
+
+	invertedGoldenRatio := (math.Sqrt(5) - 1) / 2
+	lower, upper := math.Log(0.01), math.Log(100.0)
+	for i := 0; i < 100; i++ {
+		first := upper - invertedGoldenRatio*(upper-lower)
+		second := lower + invertedGoldenRatio*(upper-lower)
+		if negativeLogLikelihood(first) < negativeLogLikelihood(second) {
+			upper = second
+		} else {
+			lower = first
+		}
+	}
+	return math.Exp((lower + upper) / 2)
+}
+
+// fitIsotonicRegression groups all non-zero confidence values into bins
+// and applies the pool adjacent violators algorithm to the shares of true
+// languages within the bins. It returns the mean confidence value and the
+// share of true languages of each resulting block, both strictly increasing.
+func fitIsotonicRegression(samples []calibrationSample) ([]float64, []float64) {
+	type block struct {
+		count, trueCount, valueSum float64
+	}
+
+	bins := make([]block, isotonicRegressionBins)
+	for _, sample := range samples {
+		for i, value := range sample.values {
+			bin := int(math.Min(value*isotonicRegressionBins, isotonicRegressionBins-1))
+			bins[bin].count++
+			bins[bin].valueSum += value
+			if i == sample.trueIndex {
+				bins[bin].trueCount++
+			}
+		}
+	}
+
+	var blocks []block
+	for _, bin := range bins {
+		if bin.count == 0 {
+			continue
+		}
+		blocks = append(blocks, bin)
+		for len(blocks) > 1 {
+			last, previous := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if previous.trueCount/previous.count < last.trueCount/last.count {
+				break
+			}
+			blocks = blocks[:len(blocks)-1]
+			blocks[len(blocks)-1] = block{
+				previous.count + last.count,
+				previous.trueCount + last.trueCount,
+				previous.valueSum + last.valueSum,
+			}
+		}
+	}
+
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	confidenceValues := make([]float64, len(blocks))
+	probabilities := make([]float64, len(blocks))
+	for i, b := range blocks {
+		confidenceValues[i] = b.valueSum / b.count
+		probabilities[i] = b.trueCount / b.count
+	}
+	return confidenceValues, probabilities
+}
+
+// calibrationBucketBoundaries returns the smallest character counts of the
+// text length buckets for the given long text threshold. The last boundary
+// is the threshold itself, from which on the ngram lengths for long texts
+// are used.
+func calibrationBucketBoundaries(longTextThreshold int) []int {
+	var boundaries []int
+	for _, minimumCharacters := range shortTextBucketBoundaries {
+		if minimumCharacters < longTextThreshold {
+			boundaries = append(boundaries, minimumCharacters)
+		}
+	}
+	return append(boundaries, longTextThreshold)
+}
+
+func calibrationBucketOf(boundaries []int, characterCount int) int {
+	bucket := 0
+	for i, minimumCharacters := range boundaries {
+		if characterCount >= minimumCharacters {
+			bucket = i
+		}
+	}
+	return bucket
+}
+
+func parseCalibrationMethod(name string) (CalibrationMethod, error) {
+	for _, method := range []CalibrationMethod{TemperatureScaling, IsotonicRegression} {
+		if method.String() == name {
+			return method, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown calibration method '%s'", name)
+}
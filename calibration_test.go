@@ -0,0 +1,290 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func confidenceValuesOf(values ...float64) []ConfidenceValue {
+	languages := []Language{English, German, French}
+	confidenceValues := make([]ConfidenceValue, len(values))
+	for i, value := range values {
+		confidenceValues[i] = newConfidenceValue(languages[i], value)
+	}
+	return confidenceValues
+}
+
+func TestFitTemperature(t *testing.T) {
+	// The true language receives the highest value in 6 of 10 samples only,
+	// so a confidence value of 0.9 is overconfident.
+	var samples []calibrationSample
+	for i := 0; i < 10; i++ {
+		trueIndex := 0
+		if i >= 6 {
+			trueIndex = 1
+		}
+		samples = append(samples, calibrationSample{[]float64{0.9, 0.1}, trueIndex})
+	}
+	assert.Greater(t, fitTemperature(samples), 1.0)
+
+	// The true language receives the highest value in all samples,
+	// so a confidence value of 0.6 is underconfident.
+	samples = []calibrationSample{{[]float64{0.6, 0.4}, 0}, {[]float64{0.6, 0.4}, 0}}
+	assert.Less(t, fitTemperature(samples), 1.0)
+
+	samples = []calibrationSample{{[]float64{1.0}, -1}}
+	assert.Equal(t, 1.0, fitTemperature(samples))
+	assert.Equal(t, 1.0, fitTemperature(nil))
+}
+
+func TestFitIsotonicRegression(t *testing.T) {
+	samples := []calibrationSample{
+		{[]float64{0.9, 0.1}, 0},
+		{[]float64{0.9, 0.1}, 1},
+		{[]float64{0.7, 0.3}, 0},
+		{[]float64{0.6, 0.4}, 1},
+	}
+	confidenceValues, probabilities := fitIsotonicRegression(samples)
+
+	assert.InDeltaSlice(t, []float64{0.5 / 3, 0.5, 2.5 / 3}, confidenceValues, 1e-9)
+	assert.InDeltaSlice(t, []float64{1.0 / 3, 0.5, 2.0 / 3}, probabilities, 1e-9)
+
+	confidenceValues, probabilities = fitIsotonicRegression(nil)
+	assert.Nil(t, confidenceValues)
+	assert.Nil(t, probabilities)
+}
+
+func TestCalibrateWithTemperatureScaling(t *testing.T) {
+	c := calibration{
+		TemperatureScaling,
+		[]calibrationBucket{
+			{MinimumCharacters: 0, Temperature: 2},
+			{MinimumCharacters: 10, Temperature: 0.5},
+		},
+	}
+
+	values := c.calibrate(confidenceValuesOf(0.8, 0.2, 0), 5)
+	assert.Equal(t, English, values[0].Language())
+	assert.InDelta(t, 2.0/3, values[0].Value(), 1e-9)
+	assert.InDelta(t, 1.0/3, values[1].Value(), 1e-9)
+	assert.Equal(t, 0.0, values[2].Value())
+
+	values = c.calibrate(confidenceValuesOf(0.8, 0.2, 0), 10)
+	assert.InDelta(t, 16.0/17, values[0].Value(), 1e-9)
+	assert.InDelta(t, 1.0/17, values[1].Value(), 1e-9)
+}
+
+func TestCalibrateWithIsotonicRegression(t *testing.T) {
+	c := calibration{
+		IsotonicRegression,
+		[]calibrationBucket{
+			{MinimumCharacters: 0, ConfidenceValues: []float64{0.2, 0.6}, Probabilities: []float64{0.1, 0.5}},
+		},
+	}
+
+	assert.InDelta(t, 0.05, c.buckets[0].interpolate(0.1), 1e-9)
+	assert.InDelta(t, 0.3, c.buckets[0].interpolate(0.4), 1e-9)
+	assert.InDelta(t, 0.5, c.buckets[0].interpolate(0.9), 1e-9)
+
+	values := c.calibrate(confidenceValuesOf(0.9, 0.1, 0), 5)
+	assert.InDelta(t, 0.5/0.55, values[0].Value(), 1e-9)
+	assert.InDelta(t, 0.05/0.55, values[1].Value(), 1e-9)
+	assert.Equal(t, 0.0, values[2].Value())
+
+	values = c.calibrate(confidenceValuesOf(1.0, 0, 0), 5)
+	assert.Equal(t, 1.0, values[0].Value())
+}
+
+func TestWriteAndReadCalibration(t *testing.T) {
+	for _, c := range []calibration{
+		{
+			TemperatureScaling,
+			[]calibrationBucket{{MinimumCharacters: 0, Temperature: 1.5}, {MinimumCharacters: 10, Temperature: 1.1}},
+		},
+		{
+			IsotonicRegression,
+			[]calibrationBucket{{MinimumCharacters: 0, ConfidenceValues: []float64{0.2, 0.6}, Probabilities: []float64{0.1, 0.5}}},
+		},
+	} {
+		var buffer bytes.Buffer
+		n, err := c.WriteTo(&buffer)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(buffer.Len()), n)
+
+		readCalibration, err := ReadCalibration(&buffer)
+		assert.NoError(t, err)
+		assert.Equal(t, c, readCalibration)
+	}
+}
+
+func TestReadCalibration_Errors(t *testing.T) {
+	testCases := []struct {
+		content         string
+		expectedMessage string
+	}{
+		{
+			`{"method": "PlattScaling", "buckets": [{"minimumCharacters": 0, "temperature": 1}]}`,
+			"unknown calibration method 'PlattScaling'",
+		},
+		{
+			`{"method": "TemperatureScaling", "buckets": []}`,
+			"calibration contains no buckets",
+		},
+		{
+			`{"method": "TemperatureScaling", "buckets": [{"minimumCharacters": 5, "temperature": 1}]}`,
+			"bucket 0 has an invalid minimum character count",
+		},
+		{
+			`{"method": "TemperatureScaling", "buckets": [{"minimumCharacters": 0, "temperature": 0}]}`,
+			"bucket 0 has a temperature which is not positive",
+		},
+		{
+			`{"method": "IsotonicRegression", "buckets": [{"minimumCharacters": 0, "confidenceValues": [0.1]}]}`,
+			"bucket 0 has a different number of confidence values and probabilities",
+		},
+		{
+			`{"method": "IsotonicRegression", "buckets": [{"minimumCharacters": 0, "confidenceValues": [0.5, 0.1], "probabilities": [0.1, 0.5]}]}`,
+			"bucket 0 has interpolation points which are not sorted",
+		},
+	}
+	for _, testCase := range testCases {
+		c, err := ReadCalibration(strings.NewReader(testCase.content))
+		assert.Nil(t, c)
+		assert.EqualError(t, err, testCase.expectedMessage)
+	}
+}
+
+func TestFitCalibration(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German).
+		Build()
+
+	texts := []LabeledText{
+		{"hello", English},
+		{"house", English},
+		{"haus", German},
+		{"maison", French},
+		{"der kleine Hund", German},
+		{"the little dog", English},
+		{"le petit chien", French},
+		{"straße", German},
+	}
+
+	for _, method := range []CalibrationMethod{TemperatureScaling, IsotonicRegression} {
+		c, err := FitCalibration(detector, texts, method)
+		assert.NoError(t, err)
+		assert.Equal(t, method, c.Method())
+
+		calibratedDetector := NewLanguageDetectorBuilder().
+			FromLanguages(English, French, German).
+			WithCalibration(c).
+			Build()
+
+		for _, text := range texts {
+			language, _ := detector.DetectLanguageOf(text.Text)
+			calibratedLanguage, _ := calibratedDetector.DetectLanguageOf(text.Text)
+			assert.Equal(t, language, calibratedLanguage)
+
+			values := detector.ComputeLanguageConfidenceValues(text.Text)
+			calibratedValues := calibratedDetector.ComputeLanguageConfidenceValues(text.Text)
+			sum := 0.0
+			for i, value := range calibratedValues {
+				assert.Equal(t, values[i].Language(), value.Language())
+				sum += value.Value()
+			}
+			assert.InDelta(t, 1.0, sum, 1e-9)
+		}
+
+		// Confidence values which are determined by the rule engine remain unchanged.
+		assert.Equal(t, 1.0, calibratedDetector.ComputeLanguageConfidence("straße", German))
+	}
+}
+
+func TestFitCalibration_LongTextThreshold(t *testing.T) {
+	texts := []LabeledText{{"hello", English}, {"haus", German}}
+
+	for _, testCase := range []struct {
+		longTextThreshold         int
+		expectedMinimumCharacters []int
+	}{
+		{120, []int{0, 10, 20, 50, 120}},
+		{30, []int{0, 10, 20, 30}},
+		{10, []int{0, 10}},
+	} {
+		detector := NewLanguageDetectorBuilder().
+			FromLanguages(English, German).
+			WithNgramLengths(NgramLengths{
+				Short:             []int{1, 2, 3, 4, 5},
+				Long:              []int{3},
+				LongTextThreshold: testCase.longTextThreshold,
+			}).
+			Build()
+
+		c, err := FitCalibration(detector, texts, TemperatureScaling)
+		assert.NoError(t, err)
+
+		var minimumCharacters []int
+		for _, bucket := range c.(calibration).buckets {
+			minimumCharacters = append(minimumCharacters, bucket.MinimumCharacters)
+		}
+		assert.Equal(t, testCase.expectedMinimumCharacters, minimumCharacters)
+	}
+}
+
+func TestFitCalibration_Errors(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		Build()
+
+	c, err := FitCalibration(detector, []LabeledText{{"hello", English}}, CalibrationMethod(5))
+	assert.Nil(t, c)
+	assert.EqualError(t, err, "unknown calibration method CalibrationMethod(5)")
+
+	c, err = FitCalibration(detector, []LabeledText{{"straße", German}, {"123", English}}, TemperatureScaling)
+	assert.Nil(t, c)
+	assert.EqualError(t, err, "none of the texts has been evaluated by the language models")
+}
+
+func TestReadLabeledTexts(t *testing.T) {
+	directoryPath, _ := os.MkdirTemp("", "linguaTestDataDirectory")
+	defer os.RemoveAll(directoryPath)
+
+	_, err := ReadLabeledTexts(directoryPath, German)
+	assert.EqualError(t, err, "directory '"+directoryPath+"' does not contain any test data files")
+
+	_, err = ReadLabeledTexts("some/relative/path", German)
+	assert.EqualError(t, err, "directory path 'some/relative/path' is not absolute")
+
+	err = os.WriteFile(filepath.Join(directoryPath, "sentences.txt"), []byte("Das ist ein Satz.\n\nNoch ein Satz.\n"), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(directoryPath, "single-words.txt"), []byte("haus\n"), 0644)
+	assert.NoError(t, err)
+
+	texts, err := ReadLabeledTexts(directoryPath, German)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]LabeledText{{"Das ist ein Satz.", German}, {"Noch ein Satz.", German}, {"haus", German}},
+		texts,
+	)
+}
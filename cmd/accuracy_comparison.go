@@ -0,0 +1,374 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"github.com/pemistahl/lingua-go"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// detectorVariant is a differently configured Lingua detector whose accuracy
+// is compared to the other variants of the same comparison.
+type detectorVariant struct {
+	name  string
+	build func() lingua.LanguageDetector
+}
+
+var comparisons = map[string][]detectorVariant{
+	"quantization": {
+		{
+			"full-precision",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+		{
+			"quantized-16bit",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithQuantizedLanguageModels(16).
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+		{
+			"quantized-8bit",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithQuantizedLanguageModels(8).
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+	},
+	"function-words": {
+		{
+			"without-function-words",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+		{
+			"with-function-words",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithFunctionWords().
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+	},
+	"rule-evidence": {
+		{
+			"hard-rule-evidence",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+		{
+			"soft-rule-evidence-1",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithSoftRuleEvidence(lingua.RuleEvidenceWeights{Detection: 1, Filter: 1}).
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+		{
+			"soft-rule-evidence-3",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithSoftRuleEvidence(lingua.RuleEvidenceWeights{Detection: 3, Filter: 3}).
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+		{
+			"soft-rule-evidence-10",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithSoftRuleEvidence(lingua.RuleEvidenceWeights{Detection: 10, Filter: 10}).
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+	},
+	"ngram-lengths": {
+		{
+			"default-ngram-lengths",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+		{
+			"short-up-to-4",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithNgramLengths(lingua.NgramLengths{Short: []int{1, 2, 3, 4}, Long: []int{3}, LongTextThreshold: 120}).
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+		{
+			"long-3-and-4",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithNgramLengths(lingua.NgramLengths{Short: []int{1, 2, 3, 4, 5}, Long: []int{3, 4}, LongTextThreshold: 120}).
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+		{
+			"long-text-threshold-60",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithNgramLengths(lingua.NgramLengths{Short: []int{1, 2, 3, 4, 5}, Long: []int{3}, LongTextThreshold: 60}).
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+	},
+	"unseen-ngram-penalty": {
+		{
+			"without-penalty",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithUnseenNgramProbability(1.0).
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+		{
+			"unseen-probability-0.01",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithUnseenNgramProbability(0.01).
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+		{
+			"unseen-probability-0.0001",
+			func() lingua.LanguageDetector {
+				return lingua.NewLanguageDetectorBuilder().
+					FromAllLanguages().
+					WithUnseenNgramProbability(0.0001).
+					WithPreloadedLanguageModels().
+					Build()
+			},
+		},
+	},
+}
+
+// categoryAccuracies holds the accuracy values of a single detector variant
+// for a single language in the order single words, word pairs, sentences.
+type categoryAccuracies [3]float64
+
+func (accuracies categoryAccuracies) average() float64 {
+	return (accuracies[0] + accuracies[1] + accuracies[2]) / 3
+}
+
+func main() {
+	if len(os.Args) < 2 || len(os.Args) > 3 {
+		printUsageAndExit()
+	}
+	comparisonName := os.Args[1]
+	variants, exists := comparisons[comparisonName]
+	if !exists {
+		printUsageAndExit()
+	}
+	if len(os.Args) == 3 {
+		variants = filterVariants(variants, os.Args[2])
+		if len(variants) == 0 {
+			printUsageAndExit()
+		}
+	}
+
+	start := time.Now()
+
+	testDataDirectory, _ := filepath.Abs("language-testdata")
+	comparisonDirectory, _ := filepath.Abs(filepath.Join("accuracy-reports", "comparisons", comparisonName))
+
+	err := os.MkdirAll(comparisonDirectory, os.ModePerm)
+	if err != nil {
+		panic("Comparison directory could not be created")
+	}
+
+	languages := languagesWithTestData(testDataDirectory)
+	testData := make(map[lingua.Language][3][]string)
+	for _, language := range languages {
+		testData[language] = [3][]string{
+			getFileContent(testDataDirectory, "single-words", language),
+			getFileContent(testDataDirectory, "word-pairs", language),
+			getFileContent(testDataDirectory, "sentences", language),
+		}
+	}
+
+	var summaries []string
+
+	for i, variant := range variants {
+		fmt.Printf("Computing accuracy values for %s... (%d/%d)\n", variant.name, i+1, len(variants))
+		detector := variant.build()
+
+		comparisonFilePath := filepath.Join(comparisonDirectory, fmt.Sprintf("%s.csv", variant.name))
+		comparisonFile, err := os.Create(comparisonFilePath)
+		if err != nil {
+			panic("CSV file could not be created")
+		}
+
+		_, err = comparisonFile.WriteString("language,average,single-words,word-pairs,sentences\n")
+		if err != nil {
+			panic("CSV header row could not be written")
+		}
+
+		var meanAccuracies categoryAccuracies
+
+		for _, language := range languages {
+			var accuracies categoryAccuracies
+			for j, texts := range testData[language] {
+				accuracies[j] = computeAccuracy(detector, language, texts)
+				meanAccuracies[j] += accuracies[j] / float64(len(languages))
+			}
+
+			row := fmt.Sprintf(
+				"%s,%s,%s,%s,%s\n",
+				language,
+				formatAccuracy(accuracies.average()),
+				formatAccuracy(accuracies[0]),
+				formatAccuracy(accuracies[1]),
+				formatAccuracy(accuracies[2]),
+			)
+			_, err = comparisonFile.WriteString(row)
+			if err != nil {
+				panic("CSV data row could not be written")
+			}
+		}
+
+		comparisonFile.Close()
+
+		summaries = append(summaries, fmt.Sprintf(
+			"%s: average %s%%, single words %s%%, word pairs %s%%, sentences %s%%",
+			variant.name,
+			formatAccuracy(meanAccuracies.average()),
+			formatAccuracy(meanAccuracies[0]),
+			formatAccuracy(meanAccuracies[1]),
+			formatAccuracy(meanAccuracies[2]),
+		))
+	}
+
+	fmt.Printf("\n%s\n", strings.Join(summaries, "\n"))
+
+	elapsed := time.Since(start)
+	fmt.Printf("\nComparison successfully written in %.0f seconds\n", elapsed.Seconds())
+}
+
+func filterVariants(variants []detectorVariant, name string) []detectorVariant {
+	for _, variant := range variants {
+		if variant.name == name {
+			return []detectorVariant{variant}
+		}
+	}
+	return nil
+}
+
+func computeAccuracy(detector lingua.LanguageDetector, language lingua.Language, texts []string) float64 {
+	if len(texts) == 0 {
+		return 0
+	}
+	correctCount := 0
+	for _, text := range texts {
+		if detectedLanguage, _ := detector.DetectLanguageOf(text); detectedLanguage == language {
+			correctCount++
+		}
+	}
+	return float64(correctCount) / float64(len(texts))
+}
+
+func formatAccuracy(accuracy float64) string {
+	return fmt.Sprintf("%.2f", accuracy*100)
+}
+
+func printUsageAndExit() {
+	var names []string
+	for name := range comparisons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("Usage: go run accuracy_comparison.go <%s> [<variant>]\n", strings.Join(names, "|"))
+	os.Exit(1)
+}
+
+// languagesWithTestData returns the languages for which test data exists.
+// Languages identified by their script alone come without language models
+// and test data, so they are not part of the accuracy reports.
+func languagesWithTestData(testDataDirectory string) []lingua.Language {
+	var languages []lingua.Language
+	for _, language := range lingua.AllLanguages() {
+		testDataFileName := fmt.Sprintf("%s.txt", strings.ToLower(language.IsoCode639_1().String()))
+		if _, err := os.Stat(filepath.Join(testDataDirectory, "sentences", testDataFileName)); err == nil {
+			languages = append(languages, language)
+		}
+	}
+	return languages
+}
+
+func getFileContent(testDataDirectory, subdirectory string, language lingua.Language) []string {
+	testDataFileName := fmt.Sprintf("%s.txt", strings.ToLower(language.IsoCode639_1().String()))
+	testDataFilePath := filepath.Join(testDataDirectory, subdirectory, testDataFileName)
+	testData, err := os.ReadFile(testDataFilePath)
+	if err != nil {
+		panic(err.Error())
+	}
+	lines := strings.Split(string(testData), "\n")
+	var filteredLines []string
+
+	for _, line := range lines {
+		if utf8.RuneCountInString(strings.TrimSpace(line)) > 0 {
+			filteredLines = append(filteredLines, line)
+		}
+	}
+	return filteredLines
+}
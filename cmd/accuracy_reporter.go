@@ -308,7 +308,7 @@ func main() {
 		panic("CSV header row could not be written")
 	}
 
-	languages := lingua.AllLanguages()
+	languages := languagesWithTestData(testDataDirectory)
 	totalLanguageCount := len(languages)
 
 	for idx, language := range languages {
@@ -458,6 +458,20 @@ func main() {
 	fmt.Printf("All accuracy reports successfully written in %.0f seconds\n", elapsed.Seconds())
 }
 
+// languagesWithTestData returns the languages for which test data exists.
+// Languages identified by their script alone come without language models
+// and test data, so they are not part of the accuracy reports.
+func languagesWithTestData(testDataDirectory string) []lingua.Language {
+	var languages []lingua.Language
+	for _, language := range lingua.AllLanguages() {
+		testDataFileName := fmt.Sprintf("%s.txt", strings.ToLower(language.IsoCode639_1().String()))
+		if _, err := os.Stat(filepath.Join(testDataDirectory, "sentences", testDataFileName)); err == nil {
+			languages = append(languages, language)
+		}
+	}
+	return languages
+}
+
 func getFileContent(testDataDirectory, subdirectory string, language lingua.Language) []string {
 	testDataFileName := fmt.Sprintf("%s.txt", strings.ToLower(language.IsoCode639_1().String()))
 	testDataFilePath := filepath.Join(testDataDirectory, subdirectory, testDataFileName)
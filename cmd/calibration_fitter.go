@@ -0,0 +1,157 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"github.com/pemistahl/lingua-go"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var calibrationMethods = map[string]lingua.CalibrationMethod{
+	"TemperatureScaling": lingua.TemperatureScaling,
+	"IsotonicRegression": lingua.IsotonicRegression,
+}
+
+var testDataCategories = []string{"single-words", "word-pairs", "sentences"}
+
+// calibrationErrorBins is the number of equally sized confidence bins
+// used for computing the expected calibration error.
+const calibrationErrorBins = 10
+
+// This program fits a calibration on every other line of the test data and
+// evaluates it on the remaining lines. The calibration is written to the
+// directory calibrations, the expected calibration error of the most likely
+// language before and after calibration is printed for each category.
+func main() {
+	if len(os.Args) != 2 {
+		printCalibrationUsageAndExit()
+	}
+	method, exists := calibrationMethods[os.Args[1]]
+	if !exists {
+		printCalibrationUsageAndExit()
+	}
+
+	start := time.Now()
+
+	testDataDirectory, _ := filepath.Abs("language-testdata")
+	calibrationDirectory, _ := filepath.Abs("calibrations")
+
+	err := os.MkdirAll(calibrationDirectory, os.ModePerm)
+	if err != nil {
+		panic("Calibration directory could not be created")
+	}
+
+	var fittingTexts []lingua.LabeledText
+	evaluationTexts := make([][]lingua.LabeledText, len(testDataCategories))
+
+	for _, language := range lingua.AllLanguages() {
+		testDataFileName := fmt.Sprintf("%s.txt", strings.ToLower(language.IsoCode639_1().String()))
+		for i, category := range testDataCategories {
+			content, err := os.ReadFile(filepath.Join(testDataDirectory, category, testDataFileName))
+			if err != nil {
+				continue
+			}
+			lineNumber := 0
+			for _, line := range strings.Split(string(content), "\n") {
+				if utf8.RuneCountInString(strings.TrimSpace(line)) == 0 {
+					continue
+				}
+				text := lingua.LabeledText{Text: line, Language: language}
+				if lineNumber%2 == 0 {
+					fittingTexts = append(fittingTexts, text)
+				} else {
+					evaluationTexts[i] = append(evaluationTexts[i], text)
+				}
+				lineNumber++
+			}
+		}
+	}
+
+	detector := lingua.NewLanguageDetectorBuilder().
+		FromAllLanguages().
+		WithPreloadedLanguageModels().
+		Build()
+
+	fmt.Printf("Fitting %s on %d texts...\n", method, len(fittingTexts))
+	calibration, err := lingua.FitCalibration(detector, fittingTexts, method)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	calibrationFile, err := os.Create(filepath.Join(calibrationDirectory, fmt.Sprintf("%s.json", method)))
+	if err != nil {
+		panic("Calibration file could not be created")
+	}
+	if _, err = calibration.WriteTo(calibrationFile); err != nil {
+		panic("Calibration file could not be written")
+	}
+	calibrationFile.Close()
+
+	calibratedDetector := lingua.NewLanguageDetectorBuilder().
+		FromAllLanguages().
+		WithCalibration(calibration).
+		Build()
+
+	for i, category := range testDataCategories {
+		fmt.Printf(
+			"%s: expected calibration error %.2f%% before, %.2f%% after calibration\n",
+			category,
+			computeExpectedCalibrationError(detector, evaluationTexts[i])*100,
+			computeExpectedCalibrationError(calibratedDetector, evaluationTexts[i])*100,
+		)
+	}
+
+	elapsed := time.Since(start)
+	fmt.Printf("\nCalibration successfully fitted in %.0f seconds\n", elapsed.Seconds())
+}
+
+// computeExpectedCalibrationError groups the texts by the confidence value
+// of their most likely language and returns the mean absolute difference
+// between the average confidence value and the accuracy of the groups,
+// weighted by the number of texts in each group.
+func computeExpectedCalibrationError(detector lingua.LanguageDetector, texts []lingua.LabeledText) float64 {
+	var counts, confidenceSums, correctCounts [calibrationErrorBins]float64
+
+	for _, text := range texts {
+		mostLikely := detector.ComputeLanguageConfidenceValues(text.Text)[0]
+		bin := int(math.Min(mostLikely.Value()*calibrationErrorBins, calibrationErrorBins-1))
+		counts[bin]++
+		confidenceSums[bin] += mostLikely.Value()
+		if mostLikely.Value() > 0 && mostLikely.Language() == text.Language {
+			correctCounts[bin]++
+		}
+	}
+
+	calibrationError := 0.0
+	for bin := range counts {
+		if counts[bin] > 0 {
+			calibrationError += math.Abs(confidenceSums[bin]-correctCounts[bin]) / float64(len(texts))
+		}
+	}
+	return calibrationError
+}
+
+func printCalibrationUsageAndExit() {
+	fmt.Println("Usage: go run calibration_fitter.go <TemperatureScaling|IsotonicRegression>")
+	os.Exit(1)
+}
@@ -19,24 +19,154 @@ package main
 import (
 	"fmt"
 	"github.com/pemistahl/lingua-go"
+	"os"
 	"runtime"
+	"text/tabwriter"
 )
 
+// languageMemoryUsage sums up the memory usage of all language models
+// of a single language.
+type languageMemoryUsage struct {
+	ngramCount     int
+	estimatedBytes uint64
+}
+
+// memoryProfile is the memory usage measured for a single detector
+// configuration.
+type memoryProfile struct {
+	languages       map[lingua.Language]languageMemoryUsage
+	estimatedBytes  uint64
+	heapAllocBytes  uint64
+	totalAllocBytes uint64
+}
+
 func main() {
-	lingua.NewLanguageDetectorBuilder().
-		FromAllLanguages().
-		WithPreloadedLanguageModels().
-		Build()
+	languages := lingua.AllLanguages()
+	if len(os.Args) > 1 {
+		languages = parseLanguages(os.Args[1:])
+	}
+
+	highAccuracyProfile := profile(languages, false, 0)
+	lowAccuracyProfile := profile(languages, true, 0)
+	quantized16BitProfile := profile(languages, false, 16)
+	quantized8BitProfile := profile(languages, false, 8)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "Language\tHigh accuracy ngrams\tHigh accuracy MB\tLow accuracy ngrams\tLow accuracy MB\t16-bit MB\t8-bit MB\t")
+	for _, language := range languages {
+		highAccuracyUsage := highAccuracyProfile.languages[language]
+		lowAccuracyUsage := lowAccuracyProfile.languages[language]
+		fmt.Fprintf(
+			writer,
+			"%s\t%d\t%s\t%d\t%s\t%s\t%s\t\n",
+			language,
+			highAccuracyUsage.ngramCount,
+			megabytes(highAccuracyUsage.estimatedBytes),
+			lowAccuracyUsage.ngramCount,
+			megabytes(lowAccuracyUsage.estimatedBytes),
+			megabytes(quantized16BitProfile.languages[language].estimatedBytes),
+			megabytes(quantized8BitProfile.languages[language].estimatedBytes),
+		)
+	}
+	fmt.Fprintf(
+		writer,
+		"Total (estimated)\t\t%s\t\t%s\t%s\t%s\t\n",
+		megabytes(highAccuracyProfile.estimatedBytes),
+		megabytes(lowAccuracyProfile.estimatedBytes),
+		megabytes(quantized16BitProfile.estimatedBytes),
+		megabytes(quantized8BitProfile.estimatedBytes),
+	)
+	fmt.Fprintf(
+		writer,
+		"Total (heap)\t\t%s\t\t%s\t%s\t%s\t\n",
+		megabytes(highAccuracyProfile.heapAllocBytes),
+		megabytes(lowAccuracyProfile.heapAllocBytes),
+		megabytes(quantized16BitProfile.heapAllocBytes),
+		megabytes(quantized8BitProfile.heapAllocBytes),
+	)
+	fmt.Fprintf(
+		writer,
+		"Allocated while loading\t\t%s\t\t%s\t%s\t%s\t\n",
+		megabytes(highAccuracyProfile.totalAllocBytes),
+		megabytes(lowAccuracyProfile.totalAllocBytes),
+		megabytes(quantized16BitProfile.totalAllocBytes),
+		megabytes(quantized8BitProfile.totalAllocBytes),
+	)
+	writer.Flush()
+}
+
+// profile preloads the language models of the given languages and measures
+// their memory usage, optionally with probabilities quantized to the given
+// number of bits. The language models are unloaded again afterwards so
+// that subsequent profiles start from the same state. The inverted ngram
+// index is not enabled, as it is not covered by lingua.LanguageModelMemoryUsage
+// and would distort the heap measurements.
+func profile(languages []lingua.Language, isLowAccuracyModeEnabled bool, quantizationBits int) memoryProfile {
+	before := readMemStats()
 
+	builder := lingua.NewLanguageDetectorBuilder().
+		FromLanguages(languages...).
+		WithPreloadedLanguageModels()
+	if isLowAccuracyModeEnabled {
+		builder.WithLowAccuracyMode()
+	}
+	if quantizationBits > 0 {
+		builder.WithQuantizedLanguageModels(quantizationBits)
+	}
+	detector := builder.Build()
+
+	after := readMemStats()
+
+	result := memoryProfile{
+		languages:       make(map[lingua.Language]languageMemoryUsage),
+		heapAllocBytes:  differenceOf(after.HeapAlloc, before.HeapAlloc),
+		totalAllocBytes: differenceOf(after.TotalAlloc, before.TotalAlloc),
+	}
+	for _, stats := range lingua.LanguageModelMemoryUsage() {
+		usage := result.languages[stats.Language()]
+		usage.ngramCount += stats.NgramCount()
+		usage.estimatedBytes += stats.EstimatedBytes()
+		result.languages[stats.Language()] = usage
+		result.estimatedBytes += stats.EstimatedBytes()
+	}
+
+	detector.UnloadLanguageModels()
+	return result
+}
+
+func readMemStats() runtime.MemStats {
+	runtime.GC()
+	runtime.GC()
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
+	return m
+}
+
+// differenceOf subtracts the memory statistics read before loading the
+// language models from those read afterwards. The heap can shrink in the
+// meantime if garbage from previous profiles is collected, so the difference
+// is clamped at zero instead of wrapping around.
+func differenceOf(after, before uint64) uint64 {
+	if after < before {
+		return 0
+	}
+	return after - before
+}
 
-	fmt.Printf("Alloc = %v MB\n", megabytes(m.Alloc))
-	fmt.Printf("TotalAlloc = %v MB\n", megabytes(m.TotalAlloc))
-	fmt.Printf("Sys = %v MB\n", megabytes(m.Sys))
-	fmt.Printf("NumGC = %v\n", m.NumGC)
+func parseLanguages(isoCodes []string) []lingua.Language {
+	var languages []lingua.Language
+	for _, isoCode := range isoCodes {
+		language := lingua.GetLanguageFromIsoCode639_1(lingua.GetIsoCode639_1FromValue(isoCode))
+		if language == lingua.Unknown {
+			fmt.Printf("Unknown ISO 639-1 code: %s\n", isoCode)
+			fmt.Println("Usage: go run memory_profiler.go [<ISO 639-1 code>...]")
+			os.Exit(1)
+		}
+		languages = append(languages, language)
+	}
+	return languages
 }
 
-func megabytes(bytes uint64) uint64 {
-	return bytes / 1000000
+func megabytes(bytes uint64) string {
+	return fmt.Sprintf("%.2f", float64(bytes)/1000000)
 }
@@ -0,0 +1,142 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"github.com/pemistahl/lingua-go"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var weightModes = map[string]bool{
+	"shared":       false,
+	"per-language": true,
+}
+
+var weightTestDataCategories = []string{"single-words", "word-pairs", "sentences"}
+
+// This program fits ngram order weights on every other line of the test data
+// and evaluates them on the remaining lines. The weights are written to the
+// directory ngram-order-weights, the mean accuracy over all languages with
+// and without the weights is printed for each category.
+func main() {
+	if len(os.Args) != 2 {
+		printWeightsUsageAndExit()
+	}
+	perLanguage, exists := weightModes[os.Args[1]]
+	if !exists {
+		printWeightsUsageAndExit()
+	}
+
+	start := time.Now()
+
+	testDataDirectory, _ := filepath.Abs("language-testdata")
+	weightsDirectory, _ := filepath.Abs("ngram-order-weights")
+
+	err := os.MkdirAll(weightsDirectory, os.ModePerm)
+	if err != nil {
+		panic("Weights directory could not be created")
+	}
+
+	var fittingTexts []lingua.LabeledText
+	evaluationTexts := make([]map[lingua.Language][]string, len(weightTestDataCategories))
+
+	for i, category := range weightTestDataCategories {
+		evaluationTexts[i] = make(map[lingua.Language][]string)
+		for _, language := range lingua.AllLanguages() {
+			testDataFileName := fmt.Sprintf("%s.txt", strings.ToLower(language.IsoCode639_1().String()))
+			content, err := os.ReadFile(filepath.Join(testDataDirectory, category, testDataFileName))
+			if err != nil {
+				continue
+			}
+			lineNumber := 0
+			for _, line := range strings.Split(string(content), "\n") {
+				if utf8.RuneCountInString(strings.TrimSpace(line)) == 0 {
+					continue
+				}
+				if lineNumber%2 == 0 {
+					fittingTexts = append(fittingTexts, lingua.LabeledText{Text: line, Language: language})
+				} else {
+					evaluationTexts[i][language] = append(evaluationTexts[i][language], line)
+				}
+				lineNumber++
+			}
+		}
+	}
+
+	detector := lingua.NewLanguageDetectorBuilder().
+		FromAllLanguages().
+		WithPreloadedLanguageModels().
+		Build()
+
+	fmt.Printf("Fitting %s ngram order weights on %d texts...\n", os.Args[1], len(fittingTexts))
+	weights, err := lingua.FitNgramOrderWeights(detector, fittingTexts, perLanguage)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	weightsFile, err := os.Create(filepath.Join(weightsDirectory, fmt.Sprintf("%s.json", os.Args[1])))
+	if err != nil {
+		panic("Weights file could not be created")
+	}
+	if _, err = weights.WriteTo(weightsFile); err != nil {
+		panic("Weights file could not be written")
+	}
+	weightsFile.Close()
+
+	weightedDetector := lingua.NewLanguageDetectorBuilder().
+		FromAllLanguages().
+		WithNgramOrderWeights(weights).
+		Build()
+
+	for i, category := range weightTestDataCategories {
+		fmt.Printf(
+			"%s: mean accuracy %.2f%% before, %.2f%% after weighting\n",
+			category,
+			computeMeanAccuracy(detector, evaluationTexts[i])*100,
+			computeMeanAccuracy(weightedDetector, evaluationTexts[i])*100,
+		)
+	}
+
+	elapsed := time.Since(start)
+	fmt.Printf("\nWeights successfully fitted in %.0f seconds\n", elapsed.Seconds())
+}
+
+// computeMeanAccuracy returns the share of correctly detected texts of
+// each language, averaged over all languages.
+func computeMeanAccuracy(detector lingua.LanguageDetector, texts map[lingua.Language][]string) float64 {
+	sum := 0.0
+	for language, languageTexts := range texts {
+		correctCount := 0
+		for _, text := range languageTexts {
+			if detectedLanguage, _ := detector.DetectLanguageOf(text); detectedLanguage == language {
+				correctCount++
+			}
+		}
+		sum += float64(correctCount) / float64(len(languageTexts))
+	}
+	return sum / float64(len(texts))
+}
+
+func printWeightsUsageAndExit() {
+	fmt.Println("Usage: go run ngram_order_weights_fitter.go <shared|per-language>")
+	os.Exit(1)
+}
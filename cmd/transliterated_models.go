@@ -0,0 +1,313 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/pemistahl/lingua-go/serialization"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// transliteration derives the language models of a script variant from
+// the language models of the script which a language is usually written in,
+// such as Serbian Latin from Serbian Cyrillic. The result is an approximation:
+// characters which are transliterated to more than one character, such as
+// љ to lj, split the ngrams of the source script, and the absolute ngram
+// frequencies have to be reconstructed from the relative ones.
+type transliteration struct {
+	sourceDirectory string
+	targetDirectory string
+	characters      map[rune]string
+}
+
+var transliterations = map[string]transliteration{
+	"sr-Latn": {
+		"sr",
+		"sr-Latn",
+		map[rune]string{
+			'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'ђ': "đ", 'е': "e", 'ж': "ž", 'з': "z", 'и': "i",
+			'ј': "j", 'к': "k", 'л': "l", 'љ': "lj", 'м': "m", 'н': "n", 'њ': "nj", 'о': "o", 'п': "p", 'р': "r",
+			'с': "s", 'т': "t", 'ћ': "ć", 'у': "u", 'ф': "f", 'х': "h", 'ц': "c", 'ч': "č", 'џ': "dž", 'ш': "š",
+		},
+	},
+}
+
+const maxNgramLength = 5
+
+var ngramNames = [maxNgramLength + 1]string{"", "unigram", "bigram", "trigram", "quadrigram", "fivegram"}
+
+func main() {
+	if len(os.Args) != 2 {
+		printTransliterationUsageAndExit()
+	}
+	transliteration, exists := transliterations[os.Args[1]]
+	if !exists {
+		printTransliterationUsageAndExit()
+	}
+
+	sourceDirectory, _ := filepath.Abs(filepath.Join("..", "language-models", transliteration.sourceDirectory))
+	targetDirectory, _ := filepath.Abs(filepath.Join("..", "language-models", transliteration.targetDirectory))
+
+	err := os.MkdirAll(targetDirectory, os.ModePerm)
+	if err != nil {
+		panic("Target directory could not be created")
+	}
+
+	sourceModels := make([]*serialization.SerializableLanguageModel, maxNgramLength+1)
+	for ngramLength := 1; ngramLength <= maxNgramLength; ngramLength++ {
+		sourceModels[ngramLength] = readLanguageModel(sourceDirectory, ngramLength)
+	}
+
+	sourceFrequencies := reconstructAbsoluteFrequencies(sourceModels)
+	targetFrequencies := transliteration.transliterateAbsoluteFrequencies(sourceFrequencies)
+
+	var checksumLines []string
+
+	for ngramLength := 1; ngramLength <= maxNgramLength; ngramLength++ {
+		probabilities := computeRelativeFrequencies(ngramLength, targetFrequencies)
+		fileName := writeLanguageModel(targetDirectory, sourceModels[ngramLength].Language, ngramLength, probabilities)
+		checksumLines = append(checksumLines, checksumLine(targetDirectory, fileName))
+		fmt.Printf("%s: %d ngrams\n", fileName, len(probabilities))
+	}
+
+	sort.Slice(checksumLines, func(i, j int) bool {
+		return checksumLines[i][sha256.Size*2:] < checksumLines[j][sha256.Size*2:]
+	})
+	err = os.WriteFile(filepath.Join(targetDirectory, "checksums.txt"), []byte(strings.Join(checksumLines, "")), 0644)
+	if err != nil {
+		panic("Checksums file could not be written")
+	}
+}
+
+// reconstructAbsoluteFrequencies recovers the absolute frequencies of the
+// ngrams from their relative frequencies. The probability of a unigram is
+// its frequency divided by the total number of unigrams, the probability of
+// a longer ngram is its frequency divided by the frequency of its prefix.
+// The total number of unigrams is estimated by assuming that the least
+// frequent bigram following the most frequent unigram occurs exactly once.
+func reconstructAbsoluteFrequencies(models []*serialization.SerializableLanguageModel) []map[string]float64 {
+	probabilities := make([]map[string]float64, len(models))
+	for ngramLength := 1; ngramLength < len(models); ngramLength++ {
+		probabilities[ngramLength] = make(map[string]float64)
+		for _, ngramSet := range models[ngramLength].NgramSets {
+			for _, ngram := range ngramSet.Ngrams {
+				probabilities[ngramLength][ngram] = ngramSet.Probability
+			}
+		}
+	}
+
+	mostFrequentUnigram := ""
+	for unigram, probability := range probabilities[1] {
+		if probability > probabilities[1][mostFrequentUnigram] {
+			mostFrequentUnigram = unigram
+		}
+	}
+	minimumBigramProbability := 1.0
+	for bigram, probability := range probabilities[2] {
+		if strings.HasPrefix(bigram, mostFrequentUnigram) {
+			minimumBigramProbability = math.Min(minimumBigramProbability, probability)
+		}
+	}
+	unigramCount := math.Round(1/minimumBigramProbability) / probabilities[1][mostFrequentUnigram]
+
+	frequencies := make([]map[string]float64, len(models))
+	frequencies[1] = make(map[string]float64)
+	for unigram, probability := range probabilities[1] {
+		frequencies[1][unigram] = math.Round(probability * unigramCount)
+	}
+	for ngramLength := 2; ngramLength < len(models); ngramLength++ {
+		frequencies[ngramLength] = make(map[string]float64)
+		for ngram, probability := range probabilities[ngramLength] {
+			prefix := string([]rune(ngram)[:ngramLength-1])
+			frequencies[ngramLength][ngram] = math.Round(probability * frequencies[ngramLength-1][prefix])
+		}
+	}
+	return frequencies
+}
+
+// transliterateAbsoluteFrequencies computes the absolute frequencies of the
+// ngrams in the target script. Each ngram of the target script starts either
+// at the beginning of a transliterated character or in the middle of it if
+// the character is transliterated to more than one character. It is counted
+// as often as the shortest ngram of the source script covering it.
+func (transliteration transliteration) transliterateAbsoluteFrequencies(
+	sourceFrequencies []map[string]float64,
+) []map[string]float64 {
+	targetFrequencies := make([]map[string]float64, len(sourceFrequencies))
+	for ngramLength := 1; ngramLength < len(targetFrequencies); ngramLength++ {
+		targetFrequencies[ngramLength] = make(map[string]float64)
+	}
+
+	for sourceLength := 1; sourceLength < len(sourceFrequencies); sourceLength++ {
+		for sourceNgram, frequency := range sourceFrequencies[sourceLength] {
+			prefix, isTransliterated := transliteration.transliterate([]rune(sourceNgram)[:sourceLength-1])
+			transliterated, isLastTransliterated := transliteration.transliterate([]rune(sourceNgram))
+			if !isTransliterated || !isLastTransliterated {
+				continue
+			}
+			firstCharacterLength := utf8.RuneCountInString(transliteration.characters[[]rune(sourceNgram)[0]])
+
+			for offset := 0; offset < firstCharacterLength; offset++ {
+				chars := []rune(transliterated)[offset:]
+				prefixLength := utf8.RuneCountInString(prefix) - offset
+				for targetLength := 1; targetLength < len(targetFrequencies) && targetLength <= len(chars); targetLength++ {
+					if targetLength > prefixLength {
+						targetFrequencies[targetLength][string(chars[:targetLength])] += frequency
+					}
+				}
+			}
+		}
+	}
+	return targetFrequencies
+}
+
+func (transliteration transliteration) transliterate(chars []rune) (string, bool) {
+	var builder strings.Builder
+	for _, chr := range chars {
+		transliterated, exists := transliteration.characters[chr]
+		if !exists {
+			return "", false
+		}
+		builder.WriteString(transliterated)
+	}
+	return builder.String(), true
+}
+
+func computeRelativeFrequencies(ngramLength int, frequencies []map[string]float64) map[string]float64 {
+	probabilities := make(map[string]float64, len(frequencies[ngramLength]))
+	totalFrequency := 0.0
+	for _, frequency := range frequencies[ngramLength] {
+		totalFrequency += frequency
+	}
+	for ngram, frequency := range frequencies[ngramLength] {
+		denominator := totalFrequency
+		if ngramLength > 1 {
+			denominator = frequencies[ngramLength-1][string([]rune(ngram)[:ngramLength-1])]
+		}
+		probabilities[ngram] = frequency / denominator
+	}
+	return probabilities
+}
+
+func readLanguageModel(directory string, ngramLength int) *serialization.SerializableLanguageModel {
+	fileName := fmt.Sprintf("%ss.pb.bin", ngramNames[ngramLength])
+	zipReader, err := zip.OpenReader(filepath.Join(directory, fileName+".zip"))
+	if err != nil {
+		panic(err.Error())
+	}
+	defer zipReader.Close()
+
+	file, err := zipReader.Open(fileName)
+	if err != nil {
+		panic(err.Error())
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		panic(err.Error())
+	}
+	var model serialization.SerializableLanguageModel
+	if err = proto.Unmarshal(content, &model); err != nil {
+		panic(err.Error())
+	}
+	return &model
+}
+
+func writeLanguageModel(
+	directory string,
+	language serialization.SerializableLanguage,
+	ngramLength int,
+	probabilities map[string]float64,
+) string {
+	probabilitiesToNgrams := make(map[float64][]string)
+	for ngram, probability := range probabilities {
+		probabilitiesToNgrams[probability] = append(probabilitiesToNgrams[probability], ngram)
+	}
+
+	var ngramSets []*serialization.SerializableNgramSet
+	for probability, ngrams := range probabilitiesToNgrams {
+		sort.Strings(ngrams)
+		ngramSets = append(ngramSets, &serialization.SerializableNgramSet{
+			Probability: probability,
+			Ngrams:      ngrams,
+		})
+	}
+	sort.Slice(ngramSets, func(i, j int) bool {
+		return ngramSets[i].Probability < ngramSets[j].Probability
+	})
+
+	serializedModel, err := proto.Marshal(&serialization.SerializableLanguageModel{
+		Language:    language,
+		NgramLength: uint32(ngramLength),
+		TotalNgrams: uint32(len(probabilities)),
+		NgramSets:   ngramSets,
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+
+	fileName := fmt.Sprintf("%ss.pb.bin", ngramNames[ngramLength])
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	protobufFileWriter, err := zipWriter.Create(fileName)
+	if err != nil {
+		panic(err.Error())
+	}
+	if _, err = protobufFileWriter.Write(serializedModel); err != nil {
+		panic(err.Error())
+	}
+	if err = zipWriter.Close(); err != nil {
+		panic(err.Error())
+	}
+
+	zipFileName := fileName + ".zip"
+	if err = os.WriteFile(filepath.Join(directory, zipFileName), buffer.Bytes(), 0644); err != nil {
+		panic(err.Error())
+	}
+	return zipFileName
+}
+
+func checksumLine(directory, fileName string) string {
+	content, err := os.ReadFile(filepath.Join(directory, fileName))
+	if err != nil {
+		panic(err.Error())
+	}
+	checksum := sha256.Sum256(content)
+	return fmt.Sprintf("%s  %s\n", hex.EncodeToString(checksum[:]), fileName)
+}
+
+func printTransliterationUsageAndExit() {
+	var tags []string
+	for tag := range transliterations {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	fmt.Printf("Usage: go run transliterated_models.go <%s>\n", strings.Join(tags, "|"))
+	os.Exit(1)
+}
@@ -20,7 +20,6 @@ import "regexp"
 
 const maxNgramLength = 5
 
-var japaneseCharacterSet = regexp.MustCompile(`^[\p{Hiragana}\p{Katakana}\p{Han}]+$`)
 var multipleWhitespace = regexp.MustCompile(`\s+`)
 var numbers = regexp.MustCompile(`\p{N}`)
 var punctuation = regexp.MustCompile(`\p{P}`)
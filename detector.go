@@ -17,20 +17,20 @@
 package lingua
 
 import (
-	"archive/zip"
-	"bytes"
+	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"github.com/pemistahl/lingua-go/serialization"
 	"github.com/shopspring/decimal"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
-	"google.golang.org/protobuf/proto"
-	"io"
 	"math"
+	"path"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 	"unicode/utf8"
 )
 
@@ -51,6 +51,20 @@ type LanguageDetector interface {
 	// detected. If this is not possible, (Unknown, false) is returned.
 	DetectLanguageOf(text string) (Language, bool)
 
+	// DetectLanguageVariantOf detects the language of the given text like
+	// DetectLanguageOf does. If the detected language is written in more than
+	// one variant, such as Simplified and Traditional Chinese or Serbian in
+	// Cyrillic and Latin script, the variant of the text is determined as well.
+	// Chinese variants are determined by characters which are used in one of
+	// the variants only, other variants by the script of the text.
+	//
+	// UnknownLanguageVariant is returned if the detected language has no
+	// variants or if the text does not contain enough of these characters.
+	// The boolean return value indicates whether a language can be reliably
+	// detected. If this is not possible, (Unknown, UnknownLanguageVariant,
+	// false) is returned.
+	DetectLanguageVariantOf(text string) (Language, LanguageVariant, bool)
+
 	// DetectMultipleLanguagesOf attempts to detect multiple languages in
 	// mixed-language text. This feature is experimental and under continuous
 	// development.
@@ -59,7 +73,9 @@ type LanguageDetector interface {
 	// contiguous single-language text section as identified by the library.
 	// Each entry consists of the identified language, a start index and an
 	// end index. The indices denote the substring that has been identified
-	// as a contiguous single-language text section.
+	// as a contiguous single-language text section. Each entry contains the
+	// script of the text section as well and, if the identified language is
+	// written in more than one variant, the variant of the text section.
 	DetectMultipleLanguagesOf(text string) []DetectionResult
 
 	// ComputeLanguageConfidenceValues computes confidence values for each
@@ -73,7 +89,8 @@ type LanguageDetector interface {
 	// a probability between 0.0 and 1.0. The probabilities of all languages
 	// will sum to 1.0. If the language is unambiguously identified by the rule
 	// engine, the value 1.0 will always be returned for this language. The
-	// other languages will receive a value of 0.0.
+	// other languages will receive a value of 0.0. Values computed by the
+	// language models are calibrated if a Calibration has been configured.
 	ComputeLanguageConfidenceValues(text string) []ConfidenceValue
 
 	// ComputeLanguageConfidence computes the confidence value for the given
@@ -85,19 +102,108 @@ type LanguageDetector interface {
 	// 1.0 will always be returned. If the given language is not supported by
 	// this detector instance, the value 0.0 will always be returned.
 	ComputeLanguageConfidence(text string, language Language) float64
+
+	// ComputeUncertainty computes the confidence values for the given input
+	// text like ComputeLanguageConfidenceValues does, together with measures
+	// of how uncertain they are: their entropy, the margin and the ratio
+	// between the two most likely languages, and the effective number of
+	// candidate languages. These allow to route texts with uncertain results
+	// to a human review, for instance.
+	ComputeUncertainty(text string) Uncertainty
+
+	// LanguagesUsingCharacter returns those languages supported by this
+	// detector whose texts may contain the given character according to the
+	// rule engine, sorted alphabetically.
+	//
+	// If the character is unique to a language, only this language is
+	// returned. Otherwise, the languages of the character evidence tables are
+	// returned if the character is listed there, or else all languages written
+	// in the alphabet which the character belongs to. Custom character
+	// evidence tables configured for this detector are taken into account.
+	LanguagesUsingCharacter(chr rune) []Language
+
+	// IsUniqueCharacter returns the language which the given character is
+	// unique to among the languages supported by this detector. If the
+	// character is used by several of these languages or by none, the second
+	// return value is false.
+	IsUniqueCharacter(chr rune) (Language, bool)
+
+	// IsReady reports whether all language models which have been configured
+	// to be preloaded in the background have been loaded into memory.
+	//
+	// If background preloading has not been enabled, this method always
+	// returns true. If background preloading has failed because a language
+	// model could not be loaded, this method returns false.
+	IsReady() bool
+
+	// WaitUntilReady blocks until all language models which have been configured
+	// to be preloaded in the background have been loaded into memory or until
+	// the given context is done, whichever happens first.
+	//
+	// If the context is done before all language models have been loaded, the
+	// context's error is returned. If at least one language model could not be
+	// loaded, a LanguageModelErrors value listing the affected language model
+	// files is returned. Otherwise, nil is returned. If background preloading
+	// has not been enabled, this method returns nil immediately.
+	WaitUntilReady(ctx context.Context) error
+
+	// UnloadLanguageModels removes the language models of all languages
+	// supported by this detector from memory.
+	//
+	// As language models are shared between all LanguageDetector instances,
+	// they are removed for the other instances as well and will be loaded
+	// again on demand. This method must not be called while language
+	// detection is in progress.
+	UnloadLanguageModels()
 }
 
 type languageDetector struct {
-	languages                     []Language
-	minimumRelativeDistance       float64
-	isLowAccuracyModeEnabled      bool
-	languagesWithUniqueCharacters []Language
-	oneLanguageAlphabets          map[alphabet]Language
-	unigramLanguageModels         *sync.Map
-	bigramLanguageModels          *sync.Map
-	trigramLanguageModels         *sync.Map
-	quadrigramLanguageModels      *sync.Map
-	fivegramLanguageModels        *sync.Map
+	languages                       []Language
+	minimumRelativeDistance         float64
+	isLowAccuracyModeEnabled        bool
+	quantizationBits                int
+	languagesWithUniqueCharacters   []Language
+	oneLanguageAlphabets            map[alphabet]Language
+	unigramLanguageModels           *sync.Map
+	bigramLanguageModels            *sync.Map
+	trigramLanguageModels           *sync.Map
+	quadrigramLanguageModels        *sync.Map
+	fivegramLanguageModels          *sync.Map
+	ngramIndex                      *ngramIndex
+	readiness                       *readiness
+	hooks                           LanguageDetectorHooks
+	rulesBeforeBuiltIns             []Rule
+	rulesAfterBuiltIns              []Rule
+	characterEvidence               *characterEvidence
+	characterLookup                 *characterLookup
+	functionWords                   map[Language]map[string]struct{}
+	ruleEvidenceWeights             *RuleEvidenceWeights
+	ruleWordShares                  RuleWordShares
+	calibration                     Calibration
+	logLanguagePriors               map[Language]float64
+	openSetThresholds               *OpenSetThresholds
+	minimumRelativeDistanceCurve    []RelativeDistancePoint
+	languageDistanceCurves          map[Language][]RelativeDistancePoint
+	ngramLengths                    NgramLengths
+	ngramOrderWeights               NgramOrderWeights
+	unseenNgramLogProbability       float64
+	isScriptVariantDetectionEnabled bool
+}
+
+// readiness tracks the completion of background preloading and the
+// error it has failed with, if any.
+type readiness struct {
+	done chan struct{}
+	err  error
+}
+
+func newReadiness() *readiness {
+	return &readiness{done: make(chan struct{})}
+}
+
+func (r *readiness) finish(err error) {
+	r.err = err
+	close(r.done)
 }
 
 func newLanguageDetector(
@@ -107,79 +213,168 @@ func newLanguageDetector(
 	isLowAccuracyModeEnabled bool,
 ) languageDetector {
 	detector := languageDetector{
-		languages,
-		minimumRelativeDistance,
-		isLowAccuracyModeEnabled,
-		collectLanguagesWithUniqueCharacters(languages),
-		collectOneLanguageAlphabets(languages),
-		&unigramModels,
-		&bigramModels,
-		&trigramModels,
-		&quadrigramModels,
-		&fivegramModels,
+		languages:                     languages,
+		minimumRelativeDistance:       minimumRelativeDistance,
+		isLowAccuracyModeEnabled:      isLowAccuracyModeEnabled,
+		languagesWithUniqueCharacters: collectLanguagesWithUniqueCharacters(languages),
+		oneLanguageAlphabets:          collectOneLanguageAlphabets(languages),
+		unigramLanguageModels:         &unigramModels,
+		bigramLanguageModels:          &bigramModels,
+		trigramLanguageModels:         &trigramModels,
+		quadrigramLanguageModels:      &quadrigramModels,
+		fivegramLanguageModels:        &fivegramModels,
+		readiness:                     newReadiness(),
+		ruleWordShares:                defaultRuleWordShares,
+		ngramLengths:                  defaultNgramLengths,
 	}
+	detector.characterLookup = detector.collectCharacterLookup()
+	detector.readiness.finish(nil)
 	if isEveryLanguageModelPreloaded {
-		detector.preloadLanguageModels(languages)
+		detector.mustPreloadLanguageModels(languages, allNgramLengths())
 	}
 	return detector
 }
 
-func (detector languageDetector) preloadLanguageModels(languages []Language) {
+func (detector languageDetector) preloadLanguageModels(languages []Language, ngramLengths []int) error {
 	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var errs LanguageModelErrors
 	for _, language := range languages {
+		if !language.hasLanguageModels() {
+			continue
+		}
 		wg.Add(1)
 		go func(language Language, wg *sync.WaitGroup) {
 			defer wg.Done()
-			loadLanguageModels(detector.trigramLanguageModels, language, 3)
-
-			if !detector.isLowAccuracyModeEnabled {
-				loadLanguageModels(detector.unigramLanguageModels, language, 1)
-				loadLanguageModels(detector.bigramLanguageModels, language, 2)
-				loadLanguageModels(detector.quadrigramLanguageModels, language, 4)
-				loadLanguageModels(detector.fivegramLanguageModels, language, 5)
+			for _, ngramLength := range ngramLengths {
+				if ngramLength != 3 && detector.isLowAccuracyModeEnabled {
+					continue
+				}
+				var modelErr *LanguageModelError
+				if err := detector.loadLanguageModel(language, ngramLength); errors.As(err, &modelErr) {
+					mutex.Lock()
+					errs = append(errs, modelErr)
+					mutex.Unlock()
+				}
 			}
 		}(language, &wg)
 	}
 	wg.Wait()
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].Path < errs[j].Path
+		})
+		return errs
+	}
+	return nil
+}
+
+func (detector languageDetector) mustPreloadLanguageModels(languages []Language, ngramLengths []int) {
+	if err := detector.preloadLanguageModels(languages, ngramLengths); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (detector languageDetector) preloadLanguageModelsInBackground(languages []Language, ngramLengths []int) {
+	err := detector.preloadLanguageModels(languages, ngramLengths)
+	if err == nil && detector.ngramIndex != nil {
+		err = detector.buildNgramIndex(detector.ngramLengths.all())
+	}
+	detector.readiness.finish(err)
+}
+
+func (detector languageDetector) IsReady() bool {
+	select {
+	case <-detector.readiness.done:
+		return detector.readiness.err == nil
+	default:
+		return false
+	}
+}
+
+func (detector languageDetector) WaitUntilReady(ctx context.Context) error {
+	select {
+	case <-detector.readiness.done:
+		return detector.readiness.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (detector languageDetector) UnloadLanguageModels() {
+	for _, ngramLength := range allNgramLengths() {
+		models := detector.languageModelsOfLength(ngramLength)
+		for _, language := range detector.languages {
+			key := languageModelKeyOf(language, detector.quantizationBits, detector.usesScriptVariantModels(language))
+			value, loaded := models.LoadAndDelete(key)
+			if _, isFailure := value.(languageModelFailure); loaded && !isFailure && detector.hooks != nil {
+				detector.hooks.OnModelEvicted(language, ngramLength)
+			}
+		}
+	}
+	if detector.ngramIndex != nil {
+		detector.ngramIndex.store(nil)
+	}
 }
 
 func (detector languageDetector) DetectLanguageOf(text string) (Language, bool) {
-	confidenceValues := detector.ComputeLanguageConfidenceValues(text)
+	startTime := time.Now()
+	language, reason := detector.detectLanguageOf(text)
+	detector.notifyDetection(language, reason, startTime)
+	return language, language != Unknown
+}
+
+func (detector languageDetector) DetectLanguageVariantOf(text string) (Language, LanguageVariant, bool) {
+	language, exists := detector.DetectLanguageOf(text)
+	return language, detectLanguageVariant(language, text), exists
+}
+
+func (detector languageDetector) detectLanguageOf(text string) (Language, DetectionReason) {
+	confidenceValues, reason := detector.computeLanguageConfidenceValues(text)
 	mostLikely := confidenceValues[0]
 	secondMostLikely := confidenceValues[1]
 
 	if mostLikely.Value() == secondMostLikely.Value() {
-		return Unknown, false
+		if reason == ReasonNgramModels {
+			reason = ReasonAmbiguousResult
+		}
+		return Unknown, reason
 	}
-	if (mostLikely.Value() - secondMostLikely.Value()) < detector.minimumRelativeDistance {
-		return Unknown, false
+	minimumRelativeDistance := detector.minimumRelativeDistanceOf(text, mostLikely.Language(), secondMostLikely.Language())
+	if (mostLikely.Value() - secondMostLikely.Value()) < minimumRelativeDistance {
+		return Unknown, ReasonAmbiguousResult
 	}
 
-	return mostLikely.Language(), true
+	return mostLikely.Language(), reason
 }
 
 func (detector languageDetector) DetectMultipleLanguagesOf(text string) []DetectionResult {
+	startTime := time.Now()
 	if len(text) == 0 {
+		detector.notifyDetection(Unknown, ReasonNoWords, startTime)
 		return []DetectionResult{}
 	}
 
 	tokenWithoutWhitespaceIndices := tokensWithoutWhitespace.FindAllStringIndex(text, -1)
 	if len(tokenWithoutWhitespaceIndices) == 0 {
+		detector.notifyDetection(Unknown, ReasonNoWords, startTime)
 		return []DetectionResult{}
 	}
 
 	var results []detectionResult
 	languageCounts := make(map[Language]int)
 
-	language, _ := detector.DetectLanguageOf(text)
-	languageCounts[language]++
+	textLanguage, textReason := detector.detectLanguageOf(text)
+	languageCounts[textLanguage]++
+
+	var language Language
 
 	for _, tokenIndex := range tokenWithoutWhitespaceIndices {
 		if tokenIndex[1]-tokenIndex[0] < 5 {
 			continue
 		}
 		word := text[tokenIndex[0]:tokenIndex[1]]
-		language, _ = detector.DetectLanguageOf(word)
+		language, _ = detector.detectLanguageOf(word)
 		languageCounts[language]++
 	}
 
@@ -207,7 +402,7 @@ func (detector languageDetector) DetectMultipleLanguagesOf(text string) []Detect
 
 		for i, tokenIndex := range tokenIndices {
 			word := text[tokenIndex[0]:tokenIndex[1]]
-			language, _ = detector.DetectLanguageOf(word)
+			language, _ = detector.detectLanguageOf(word)
 
 			if i == 0 {
 				currentLanguage = language
@@ -258,13 +453,56 @@ func (detector languageDetector) DetectMultipleLanguagesOf(text string) []Detect
 
 	detectionResults := make([]DetectionResult, len(results))
 	for i, result := range results {
+		section := text[result.startIndex:result.endIndex]
+		result.variant = detectLanguageVariant(result.language, section)
+		result.script = dominantScriptOf(result.language, section)
 		detectionResults[i] = DetectionResult(result)
 	}
 
+	detector.notifyDetection(textLanguage, textReason, startTime)
 	return detectionResults
 }
 
 func (detector languageDetector) ComputeLanguageConfidenceValues(text string) []ConfidenceValue {
+	startTime := time.Now()
+	values, reason := detector.computeLanguageConfidenceValues(text)
+	detector.notifyDetection(mostLikelyLanguage(values), reason, startTime)
+	return detector.calibrateConfidenceValues(text, values, reason)
+}
+
+func (detector languageDetector) computeLanguageConfidenceValues(text string) ([]ConfidenceValue, DetectionReason) {
+	values, scores, reason := detector.computeNgramScores(text)
+	if reason != ReasonNgramModels {
+		return values, reason
+	}
+
+	summedUpProbabilities := sumUpProbabilities(scores, detector.logLanguagePriors, detector.ngramOrderWeights)
+
+	if len(summedUpProbabilities) == 0 {
+		sort.Sort(values)
+		return values, ReasonNoNgramMatches
+	}
+
+	return detector.computeConfidenceValues(values, scores.probabilityMaps, summedUpProbabilities), ReasonNgramModels
+}
+
+// ngramScores holds the summed up logarithmized probabilities of the ngrams
+// of a text for each of the evaluated ngram lengths, in the same order as
+// these, together with everything else needed to combine them.
+type ngramScores struct {
+	ngramLengths      []int
+	probabilityMaps   []map[Language]float64
+	unigramCounts     map[Language]uint32
+	filteredLanguages []Language
+	ruleEvidence      map[Language]float64
+}
+
+// computeNgramScores applies the rules to the given text and looks up its
+// ngrams in the language models of the remaining languages. The returned
+// confidence values are already final if the returned reason is anything
+// else than ReasonNgramModels. Otherwise, they are all 0.0 and have to be
+// computed from the returned scores.
+func (detector languageDetector) computeNgramScores(text string) (confidenceValueSlice, ngramScores, DetectionReason) {
 	values := make(confidenceValueSlice, len(detector.languages))
 	for i, language := range detector.languages {
 		values[i] = newConfidenceValue(language, 0)
@@ -274,10 +512,10 @@ func (detector languageDetector) ComputeLanguageConfidenceValues(text string) []
 
 	if len(words) == 0 {
 		sort.Sort(values)
-		return values
+		return values, ngramScores{}, ReasonNoWords
 	}
 
-	languageDetectedByRules := detector.detectLanguageWithRules(words)
+	languageDetectedByRules, filteredLanguages, ruleEvidence := detector.applyRules(words)
 
 	if languageDetectedByRules != Unknown {
 		for i := range values {
@@ -287,11 +525,9 @@ func (detector languageDetector) ComputeLanguageConfidenceValues(text string) []
 			}
 		}
 		sort.Sort(values)
-		return values
+		return values, ngramScores{}, ReasonRules
 	}
 
-	filteredLanguages := detector.filterLanguagesByRules(words)
-
 	if len(filteredLanguages) == 1 {
 		languageDetectedByFilter := filteredLanguages[0]
 		for i := range values {
@@ -301,36 +537,37 @@ func (detector languageDetector) ComputeLanguageConfidenceValues(text string) []
 			}
 		}
 		sort.Sort(values)
-		return values
+		return values, ngramScores{}, ReasonRuleFilter
 	}
 
-	characterCount := 0
-	for _, word := range words {
-		characterCount += utf8.RuneCountInString(word)
-	}
+	characterCount := countCharacters(words)
 
 	if detector.isLowAccuracyModeEnabled && characterCount < 3 {
 		sort.Sort(values)
-		return values
+		return values, ngramScores{}, ReasonTooFewCharacters
 	}
 
-	var ngramLengthRange []int
+	if detector.isUnsupportedLanguage(words, filteredLanguages) {
+		sort.Sort(values)
+		return values, ngramScores{}, ReasonUnsupportedLanguage
+	}
+
+	ngramLengthRange := detector.ngramLengths.forCharacterCount(characterCount)
 
-	if characterCount >= 120 || detector.isLowAccuracyModeEnabled {
+	if detector.isLowAccuracyModeEnabled {
 		ngramLengthRange = []int{3}
-	} else {
-		ngramLengthRange = []int{1, 2, 3, 4, 5}
 	}
 
-	probabilityChannel := make(chan map[Language]float64, len(ngramLengthRange))
+	probabilityChannels := make([]chan map[Language]float64, len(ngramLengthRange))
 	unigramCountChannel := make(chan map[Language]uint32, 1)
 
-	for _, ngramLength := range ngramLengthRange {
+	for i, ngramLength := range ngramLengthRange {
+		probabilityChannels[i] = make(chan map[Language]float64, 1)
 		go detector.lookUpLanguageModels(
 			words,
 			ngramLength,
 			filteredLanguages,
-			probabilityChannel,
+			probabilityChannels[i],
 			unigramCountChannel,
 		)
 	}
@@ -340,19 +577,21 @@ func (detector languageDetector) ComputeLanguageConfidenceValues(text string) []
 		unigramCounts = <-unigramCountChannel
 	}
 
-	probabilityMaps := getProbabilityMaps(probabilityChannel, ngramLengthRange)
-	summedUpProbabilities := sumUpProbabilities(probabilityMaps, unigramCounts, filteredLanguages)
-
-	if len(summedUpProbabilities) == 0 {
-		sort.Sort(values)
-		return values
+	scores := ngramScores{
+		ngramLengths:      ngramLengthRange,
+		probabilityMaps:   getProbabilityMaps(probabilityChannels),
+		unigramCounts:     unigramCounts,
+		filteredLanguages: filteredLanguages,
+		ruleEvidence:      ruleEvidence,
 	}
-
-	return detector.computeConfidenceValues(values, probabilityMaps, summedUpProbabilities)
+	return values, scores, ReasonNgramModels
 }
 
 func (detector languageDetector) ComputeLanguageConfidence(text string, language Language) float64 {
-	confidenceValues := detector.ComputeLanguageConfidenceValues(text)
+	startTime := time.Now()
+	confidenceValues, reason := detector.computeLanguageConfidenceValues(text)
+	detector.notifyDetection(mostLikelyLanguage(confidenceValues), reason, startTime)
+	confidenceValues = detector.calibrateConfidenceValues(text, confidenceValues, reason)
 	for _, confidenceValue := range confidenceValues {
 		if confidenceValue.Language() == language {
 			return confidenceValue.Value()
@@ -361,12 +600,27 @@ func (detector languageDetector) ComputeLanguageConfidence(text string, language
 	return 0
 }
 
-func getProbabilityMaps(
-	probabilityChannel <-chan map[Language]float64,
-	ngramLengthRange []int,
-) []map[Language]float64 {
-	probabilityMaps := make([]map[Language]float64, len(ngramLengthRange))
-	for i := range ngramLengthRange {
+func (detector languageDetector) ComputeUncertainty(text string) Uncertainty {
+	return newUncertainty(detector.ComputeLanguageConfidenceValues(text))
+}
+
+// calibrateConfidenceValues applies the calibration of this detector, if any,
+// to confidence values which have been computed by the language models.
+// Language detection itself always relies on the uncalibrated values.
+func (detector languageDetector) calibrateConfidenceValues(
+	text string,
+	values []ConfidenceValue,
+	reason DetectionReason,
+) []ConfidenceValue {
+	if detector.calibration == nil || reason != ReasonNgramModels {
+		return values
+	}
+	return detector.calibration.calibrate(values, countCharacters(splitTextIntoWords(text)))
+}
+
+func getProbabilityMaps(probabilityChannels []chan map[Language]float64) []map[Language]float64 {
+	probabilityMaps := make([]map[Language]float64, len(probabilityChannels))
+	for i, probabilityChannel := range probabilityChannels {
 		probabilityMaps[i] = <-probabilityChannel
 	}
 	return probabilityMaps
@@ -376,36 +630,34 @@ func splitTextIntoWords(text string) []string {
 	return letters.FindAllString(strings.ToLower(text), -1)
 }
 
+func countCharacters(words []string) int {
+	characterCount := 0
+	for _, word := range words {
+		characterCount += utf8.RuneCountInString(word)
+	}
+	return characterCount
+}
+
 func (detector languageDetector) detectLanguageWithRules(words []string) Language {
 	totalLanguageCounts := make(map[Language]uint32)
-	halfWordCount := float64(len(words)) * 0.5
+	minimumUnknownCount := float64(len(words)) * detector.ruleWordShares.Unknown
 
 	for _, word := range words {
 		wordLanguageCounts := make(map[Language]uint32)
 
-		for _, chr := range []rune(word) {
-			char := string(chr)
-			isMatch := false
-
-			for alphabet, language := range detector.oneLanguageAlphabets {
-				if alphabet.matches(char) {
+		for _, chr := range word {
+			alphabet, hasAlphabet := alphabetOf(chr)
+
+			if language, exists := detector.oneLanguageAlphabets[alphabet]; hasAlphabet && exists {
+				wordLanguageCounts[language]++
+			} else if hasAlphabet && alphabet == han {
+				wordLanguageCounts[Chinese]++
+			} else if hasAlphabet && (alphabet == hiragana || alphabet == katakana) {
+				wordLanguageCounts[Japanese]++
+			} else if (hasAlphabet && (alphabet == latin || alphabet == cyrillic || alphabet == devanagari)) ||
+				detector.characterEvidence != nil {
+				for _, language := range detector.characterLookup.uniqueCharacterLanguages[chr] {
 					wordLanguageCounts[language]++
-					isMatch = true
-					break
-				}
-			}
-
-			if !isMatch {
-				if han.matches(char) {
-					wordLanguageCounts[Chinese]++
-				} else if japaneseCharacterSet.MatchString(char) {
-					wordLanguageCounts[Japanese]++
-				} else if latin.matches(char) || cyrillic.matches(char) || devanagari.matches(char) {
-					for _, language := range detector.languagesWithUniqueCharacters {
-						if strings.Contains(language.uniqueCharacters(), char) {
-							wordLanguageCounts[language]++
-						}
-					}
 				}
 			}
 		}
@@ -449,7 +701,7 @@ func (detector languageDetector) detectLanguageWithRules(words []string) Languag
 	if value, exists := totalLanguageCounts[Unknown]; exists {
 		unknownLanguageCount = float64(value)
 	}
-	if unknownLanguageCount < halfWordCount {
+	if unknownLanguageCount < minimumUnknownCount {
 		delete(totalLanguageCounts, Unknown)
 	}
 	if len(totalLanguageCounts) == 0 {
@@ -483,20 +735,33 @@ func (detector languageDetector) detectLanguageWithRules(words []string) Languag
 }
 
 func (detector languageDetector) filterLanguagesByRules(words []string) []Language {
+	filteredLanguages, isFiltered := detector.filterLanguagesByAlphabets(words)
+
+	if !isFiltered {
+		return filteredLanguages
+	}
+
+	if languageSubset := detector.filterLanguagesByCharacters(words, filteredLanguages); len(languageSubset) > 0 {
+		return languageSubset
+	}
+
+	return filteredLanguages
+}
+
+// filterLanguagesByAlphabets returns the languages written in the alphabet
+// which most words of the text belong to. If there is no such alphabet, all
+// languages of the detector are returned and the second return value is false.
+func (detector languageDetector) filterLanguagesByAlphabets(words []string) ([]Language, bool) {
 	detectedAlphabets := make(map[alphabet]uint32)
-	halfWordCount := float64(len(words)) * 0.5
 
 	for _, word := range words {
-		for _, alphabet := range allAlphabets() {
-			if alphabet.matches(word) {
-				detectedAlphabets[alphabet]++
-				break
-			}
+		if alphabet, exists := alphabetOfWord(word); exists {
+			detectedAlphabets[alphabet]++
 		}
 	}
 
 	if len(detectedAlphabets) == 0 {
-		return detector.languages
+		return detector.languages, false
 	}
 
 	if len(detectedAlphabets) > 1 {
@@ -505,7 +770,7 @@ func (detector languageDetector) filterLanguagesByRules(words []string) []Langua
 			distinctAlphabetCounts[count] = struct{}{}
 		}
 		if len(distinctAlphabetCounts) == 1 {
-			return detector.languages
+			return detector.languages, false
 		}
 	}
 
@@ -518,26 +783,28 @@ func (detector languageDetector) filterLanguagesByRules(words []string) []Langua
 	var filteredLanguages []Language
 
 	for _, language := range detector.languages {
-		if slices.Contains(language.alphabets(), mostFrequentAlphabet) {
+		if slices.Contains(detector.alphabetsOf(language), mostFrequentAlphabet) {
 			filteredLanguages = append(filteredLanguages, language)
 		}
 	}
 
+	return filteredLanguages, true
+}
+
+// filterLanguagesByCharacters returns those of the given languages whose
+// characters occur in a sufficient share of the words of the text.
+func (detector languageDetector) filterLanguagesByCharacters(words []string, filteredLanguages []Language) []Language {
 	languageCounts := make(map[Language]uint32)
+	minimumCount := float64(len(words)) * detector.ruleWordShares.Characters
 
-	for characters, languages := range charsToLanguagesMapping {
-		var relevantLanguages []Language
-		for _, language := range languages {
-			if slices.Contains(filteredLanguages, language) {
-				relevantLanguages = append(relevantLanguages, language)
+	for _, word := range words {
+		for i, chr := range word {
+			if strings.ContainsRune(word[:i], chr) {
+				continue
 			}
-		}
-		for _, word := range words {
-			for _, character := range []rune(characters) {
-				if strings.ContainsRune(word, character) {
-					for _, language := range relevantLanguages {
-						languageCounts[language]++
-					}
+			for _, language := range detector.characterLookup.characterLanguages[chr] {
+				if slices.Contains(filteredLanguages, language) {
+					languageCounts[language]++
 				}
 			}
 		}
@@ -546,16 +813,12 @@ func (detector languageDetector) filterLanguagesByRules(words []string) []Langua
 	var languageSubset []Language
 
 	for language, count := range languageCounts {
-		if float64(count) >= halfWordCount {
+		if float64(count) >= minimumCount {
 			languageSubset = append(languageSubset, language)
 		}
 	}
 
-	if len(languageSubset) > 0 {
-		return languageSubset
-	}
-
-	return filteredLanguages
+	return languageSubset
 }
 
 func (detector languageDetector) lookUpLanguageModels(
@@ -590,9 +853,15 @@ func (detector languageDetector) computeLanguageProbabilities(
 	ngramModel testDataLanguageModel,
 	filteredLanguages []Language,
 ) map[Language]float64 {
+	if entries := detector.availableNgramIndexEntries(); entries != nil {
+		return detector.computeLanguageProbabilitiesFromIndex(entries, ngramModel, filteredLanguages)
+	}
 	probabilities := make(map[Language]float64)
 	for _, language := range filteredLanguages {
-		sum := detector.computeSumOfNgramProbabilities(language, ngramModel)
+		sum, err := detector.computeSumOfNgramProbabilities(language, ngramModel)
+		if err != nil {
+			continue
+		}
 		if sum < 0 {
 			probabilities[language] = sum
 		}
@@ -615,8 +884,14 @@ func (detector languageDetector) computeConfidenceValues(
 	// So we simply set the probability of the most likely language to 1.0 and
 	// leave the other languages at 0.0.
 	if denominator.IsZero() {
-		// For very long inputs, only trigrams are used, so we safely access them at index 0.
-		probabilityMap := probabilityMaps[0]
+		// Very long inputs may be evaluated with more than one ngram length,
+		// so the log probabilities of all of them are summed up.
+		probabilityMap := make(map[Language]float64)
+		for _, languageProbabilities := range probabilityMaps {
+			for language, probability := range languageProbabilities {
+				probabilityMap[language] += probability
+			}
+		}
 
 		var languages []Language
 		for language := range probabilityMap {
@@ -650,45 +925,109 @@ func (detector languageDetector) computeConfidenceValues(
 	return confidenceValues
 }
 
-func (detector languageDetector) computeSumOfNgramProbabilities(language Language, ngramModel testDataLanguageModel) float64 {
+// computeSumOfNgramProbabilities returns the summed up logarithmized
+// probabilities of the ngrams of the given model in the given language.
+// An error is returned if one of the language models cannot be loaded.
+func (detector languageDetector) computeSumOfNgramProbabilities(
+	language Language,
+	ngramModel testDataLanguageModel,
+) (float64, error) {
 	sum := 0.0
+	matchedNgramCount := 0
 	for _, ngrams := range ngramModel.ngrams {
 		for _, n := range ngrams {
-			probability := detector.lookUpNgramProbability(language, n)
+			probability, err := detector.lookUpNgramProbability(language, n)
+			if err != nil {
+				return 0, err
+			}
 			if probability > 0 {
 				sum += math.Log(probability)
+				matchedNgramCount++
 				break
 			}
 		}
 	}
+	return detector.penalizeUnseenNgrams(sum, len(ngramModel.ngrams)-matchedNgramCount), nil
+}
+
+// penalizeUnseenNgrams adds the logarithmized unseen ngram probability of
+// this detector to the given sum for each ngram which has not been found in
+// the language models at any order. It is 0.0 unless configured otherwise,
+// which disables the penalty. Sums of languages without any matching ngram
+// remain 0.0, so that these languages are still left out.
+func (detector languageDetector) penalizeUnseenNgrams(sum float64, unseenNgramCount int) float64 {
+	if sum < 0 {
+		sum += float64(unseenNgramCount) * detector.unseenNgramLogProbability
+	}
 	return sum
 }
 
-func (detector languageDetector) lookUpNgramProbability(language Language, ngrm ngram) float64 {
+// lookUpNgramProbability returns the probability of the given ngram in the
+// language model of the given language, loading the language model if
+// necessary. If it cannot be loaded, the error is returned, so that the
+// caller can leave the language out.
+func (detector languageDetector) lookUpNgramProbability(language Language, ngrm ngram) (float64, error) {
 	ngramLength := utf8.RuneCountInString(ngrm.value)
-	var models map[string]float64
+	models := detector.languageModelsOfLength(ngramLength)
+	withScriptVariants := detector.usesScriptVariantModels(language)
+
+	var probability float64
+	var err error
+
+	switch detector.quantizationBits {
+	case 8:
+		var model quantizedLanguageModel[uint8]
+		model, err = loadQuantizedLanguageModels[uint8](models, language, ngramLength, 8, withScriptVariants, detector.hooks)
+		probability = model.probability(ngrm.value)
+	case 16:
+		var model quantizedLanguageModel[uint16]
+		model, err = loadQuantizedLanguageModels[uint16](models, language, ngramLength, 16, withScriptVariants, detector.hooks)
+		probability = model.probability(ngrm.value)
+	default:
+		var model map[string]float64
+		model, err = loadLanguageModels(models, language, ngramLength, withScriptVariants, detector.hooks)
+		probability = model[ngrm.value]
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	return probability, nil
+}
+
+func (detector languageDetector) loadLanguageModel(language Language, ngramLength int) error {
+	models := detector.languageModelsOfLength(ngramLength)
+	withScriptVariants := detector.usesScriptVariantModels(language)
+
+	var err error
+	switch detector.quantizationBits {
+	case 8:
+		_, err = loadQuantizedLanguageModels[uint8](models, language, ngramLength, 8, withScriptVariants, detector.hooks)
+	case 16:
+		_, err = loadQuantizedLanguageModels[uint16](models, language, ngramLength, 16, withScriptVariants, detector.hooks)
+	default:
+		_, err = loadLanguageModels(models, language, ngramLength, withScriptVariants, detector.hooks)
+	}
+	return err
+}
 
+func (detector languageDetector) languageModelsOfLength(ngramLength int) *sync.Map {
 	switch ngramLength {
 	case 5:
-		models = loadLanguageModels(detector.fivegramLanguageModels, language, ngramLength)
+		return detector.fivegramLanguageModels
 	case 4:
-		models = loadLanguageModels(detector.quadrigramLanguageModels, language, ngramLength)
+		return detector.quadrigramLanguageModels
 	case 3:
-		models = loadLanguageModels(detector.trigramLanguageModels, language, ngramLength)
+		return detector.trigramLanguageModels
 	case 2:
-		models = loadLanguageModels(detector.bigramLanguageModels, language, ngramLength)
+		return detector.bigramLanguageModels
 	case 1:
-		models = loadLanguageModels(detector.unigramLanguageModels, language, ngramLength)
+		return detector.unigramLanguageModels
 	case 0:
 		panic("zerogram detected")
 	default:
 		panic(fmt.Sprintf("unsupported ngram length detected: %v", ngramLength))
 	}
-
-	if frequency, exists := models[ngrm.value]; exists {
-		return frequency
-	}
-	return 0
 }
 
 func (detector languageDetector) countUnigrams(
@@ -696,10 +1035,19 @@ func (detector languageDetector) countUnigrams(
 	unigramModel testDataLanguageModel,
 	filteredLanguages []Language,
 ) {
+	if entries := detector.availableNgramIndexEntries(); entries != nil {
+		unigramCountChannel <- detector.countUnigramsFromIndex(entries, unigramModel, filteredLanguages)
+		return
+	}
 	unigramCounts := make(map[Language]uint32)
 	for _, language := range filteredLanguages {
 		for _, unigrams := range unigramModel.ngrams {
-			if detector.lookUpNgramProbability(language, unigrams[0]) > 0 {
+			probability, err := detector.lookUpNgramProbability(language, unigrams[0])
+			if err != nil {
+				delete(unigramCounts, language)
+				break
+			}
+			if probability > 0 {
 				unigramCounts[language]++
 			}
 		}
@@ -708,31 +1056,42 @@ func (detector languageDetector) countUnigrams(
 }
 
 func sumUpProbabilities(
-	probabilityMaps []map[Language]float64,
-	unigramCounts map[Language]uint32,
-	filteredLanguages []Language,
+	scores ngramScores,
+	logLanguagePriors map[Language]float64,
+	weights NgramOrderWeights,
 ) map[Language]decimal.Decimal {
 	summedUpProbabilities := make(map[Language]decimal.Decimal)
-	hasUnigramCounts := unigramCounts != nil
-	for _, language := range filteredLanguages {
+	for _, language := range scores.filteredLanguages {
 		sum := 0.0
-		for _, probabilities := range probabilityMaps {
-			if probability, exists := probabilities[language]; exists {
-				sum += probability
-			}
-		}
-		if hasUnigramCounts {
-			if unigramCount, exists := unigramCounts[language]; exists {
-				sum /= float64(unigramCount)
+		for i, probability := range scores.normalizedProbabilities(language) {
+			if weights != nil {
+				probability *= weights.weightOf(language, scores.ngramLengths[i])
 			}
+			sum += probability
 		}
 		if sum != 0 {
-			summedUpProbabilities[language] = computeExponent(sum)
+			summedUpProbabilities[language] = computeExponent(sum + scores.ruleEvidence[language] + logLanguagePriors[language])
 		}
 	}
 	return summedUpProbabilities
 }
 
+// normalizedProbabilities returns the summed up logarithmized probabilities
+// of the given language for each ngram length. If unigrams have been looked
+// up, they are divided by the number of unigrams found in the language
+// models of the language. Missing probabilities are 0.0.
+func (scores ngramScores) normalizedProbabilities(language Language) []float64 {
+	divisor := 1.0
+	if unigramCount, exists := scores.unigramCounts[language]; exists {
+		divisor = float64(unigramCount)
+	}
+	probabilities := make([]float64, len(scores.probabilityMaps))
+	for i, probabilityMap := range scores.probabilityMaps {
+		probabilities[i] = probabilityMap[language] / divisor
+	}
+	return probabilities
+}
+
 func computeExponent(value float64) decimal.Decimal {
 	exponent := math.Exp(value)
 	if exponent > 0 {
@@ -749,20 +1108,25 @@ func loadLanguageModels(
 	languageModels *sync.Map,
 	language Language,
 	ngramLength int,
-) map[string]float64 {
-	existingModels, exists := languageModels.Load(language)
+	withScriptVariants bool,
+	hooks LanguageDetectorHooks,
+) (map[string]float64, error) {
+	key := languageModelKeyOf(language, 0, withScriptVariants)
+	existingModels, exists := languageModels.Load(key)
 	if exists {
-		return existingModels.(map[string]float64)
+		if failure, isFailure := existingModels.(languageModelFailure); isFailure {
+			return nil, failure.err
+		}
+		return existingModels.(map[string]float64), nil
 	}
 
-	protobufData := loadProtobufData(language, ngramLength)
-	if protobufData == nil {
-		return nil
+	startTime := time.Now()
+	model, err := loadSerializableLanguageModel(language, ngramLength, withScriptVariants)
+	if err != nil {
+		return nil, rememberLanguageModelFailure(languageModels, key, language, ngramLength, err, hooks)
 	}
-
-	model := serialization.SerializableLanguageModel{}
-	if err := proto.Unmarshal(protobufData, &model); err != nil {
-		panic(err.Error())
+	if model == nil {
+		return nil, nil
 	}
 
 	modelMap := make(map[string]float64, model.TotalNgrams)
@@ -772,23 +1136,129 @@ func loadLanguageModels(
 		}
 	}
 
-	languageModels.Store(language, modelMap)
-	return modelMap
+	languageModels.Store(key, modelMap)
+	if hooks != nil {
+		hooks.OnModelLoaded(language, ngramLength, time.Since(startTime), len(modelMap))
+	}
+	return modelMap, nil
 }
 
-func loadProtobufData(language Language, ngramLength int) []byte {
-	ngramName := getNgramNameByLength(ngramLength)
-	isoCode := strings.ToLower(language.IsoCode639_1().String())
-	zipFilePath := fmt.Sprintf("language-models/%s/%ss.pb.bin.zip", isoCode, ngramName)
-	zipFileBytes, err := languageModels.ReadFile(zipFilePath)
+func loadQuantizedLanguageModels[T quantizationLevel](
+	languageModels *sync.Map,
+	language Language,
+	ngramLength int,
+	bits int,
+	withScriptVariants bool,
+	hooks LanguageDetectorHooks,
+) (quantizedLanguageModel[T], error) {
+	key := languageModelKeyOf(language, bits, withScriptVariants)
+	existingModels, exists := languageModels.Load(key)
+	if exists {
+		if failure, isFailure := existingModels.(languageModelFailure); isFailure {
+			return quantizedLanguageModel[T]{}, failure.err
+		}
+		return existingModels.(quantizedLanguageModel[T]), nil
+	}
+
+	startTime := time.Now()
+	model, err := loadSerializableLanguageModel(language, ngramLength, withScriptVariants)
 	if err != nil {
-		return nil
+		return quantizedLanguageModel[T]{}, rememberLanguageModelFailure(languageModels, key, language, ngramLength, err, hooks)
+	}
+	if model == nil {
+		return quantizedLanguageModel[T]{}, nil
+	}
+
+	quantizedModel := newQuantizedLanguageModel[T](model, bits)
+	languageModels.Store(key, quantizedModel)
+	if hooks != nil {
+		hooks.OnModelLoaded(language, ngramLength, time.Since(startTime), quantizedModel.ngramCount())
+	}
+	return quantizedModel, nil
+}
+
+// languageModelFailure is stored in place of a language model which cannot
+// be loaded, so that the failure is not reproduced by reading, verifying and
+// decompressing the language model again for every text. It is removed
+// together with the language models by UnloadLanguageModels.
+type languageModelFailure struct {
+	err error
+}
+
+// rememberLanguageModelFailure stores the given error in place of the
+// language model and notifies the hooks, unless another goroutine has done
+// so already. It returns the error which has been stored.
+func rememberLanguageModelFailure(
+	languageModels *sync.Map,
+	key any,
+	language Language,
+	ngramLength int,
+	err error,
+	hooks LanguageDetectorHooks,
+) error {
+	existingValue, loaded := languageModels.LoadOrStore(key, languageModelFailure{err})
+	if loaded {
+		if failure, isFailure := existingValue.(languageModelFailure); isFailure {
+			return failure.err
+		}
+		return err
+	}
+	if hooks != nil {
+		hooks.OnModelLoadFailed(language, ngramLength, err)
+	}
+	return err
+}
+
+// loadSerializableLanguageModel loads the embedded language model of the given
+// language and ngram length after verifying its checksum. If the language
+// has no language models at all or if no checksum is recorded for the
+// language model, it does not exist, and nil is returned without an error.
+// If withScriptVariants is true, the language models of script variants with
+// models of their own are merged into the returned one. As they are written
+// in different scripts, their ngrams never collide.
+func loadSerializableLanguageModel(
+	language Language,
+	ngramLength int,
+	withScriptVariants bool,
+) (*serialization.SerializableLanguageModel, error) {
+	if !language.hasLanguageModels() {
+		return nil, nil
+	}
+	model, err := loadSerializableLanguageModelFromDirectory(languageModelDirectory(language), ngramLength)
+	if model == nil || !withScriptVariants {
+		return model, err
+	}
+	for _, directory := range language.scriptVariantModelDirectories() {
+		variantModel, err := loadSerializableLanguageModelFromDirectory(directory, ngramLength)
+		if err != nil {
+			return nil, err
+		}
+		if variantModel != nil {
+			model = &serialization.SerializableLanguageModel{
+				Language:    model.Language,
+				NgramLength: model.NgramLength,
+				TotalNgrams: model.TotalNgrams + variantModel.TotalNgrams,
+				NgramSets:   append(slices.Clone(model.NgramSets), variantModel.NgramSets...),
+			}
+		}
+	}
+	return model, nil
+}
+
+func loadSerializableLanguageModelFromDirectory(
+	directory string,
+	ngramLength int,
+) (*serialization.SerializableLanguageModel, error) {
+	checksums, err := loadEmbeddedChecksums(directory)
+	if err != nil {
+		return nil, &LanguageModelError{path.Join(directory, checksumsFileName), err}
+	}
+	fileName := languageModelFileName(ngramLength)
+	checksum, exists := checksums[fileName]
+	if !exists {
+		return nil, nil
 	}
-	zipFile, _ := zip.NewReader(bytes.NewReader(zipFileBytes), int64(len(zipFileBytes)))
-	protobufFile, _ := zipFile.File[0].Open()
-	defer protobufFile.Close()
-	protobufFileContent, _ := io.ReadAll(protobufFile)
-	return protobufFileContent
+	return loadVerifiedLanguageModel(languageModels, directory, fileName, checksum)
 }
 
 func collectLanguagesWithUniqueCharacters(languages []Language) []Language {
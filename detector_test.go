@@ -17,8 +17,10 @@
 package lingua
 
 import (
+	"context"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slices"
 	"math"
 	"sync"
 	"testing"
@@ -163,7 +165,7 @@ func newDetectorForEnglishAndGerman() languageDetector {
 	fivegramLanguageModels.Store(English, fivegramModelForEnglish)
 	fivegramLanguageModels.Store(German, fivegramModelForGerman)
 
-	return languageDetector{
+	detector := languageDetector{
 		languages:                     languages,
 		minimumRelativeDistance:       0.0,
 		isLowAccuracyModeEnabled:      false,
@@ -174,7 +176,11 @@ func newDetectorForEnglishAndGerman() languageDetector {
 		trigramLanguageModels:         &trigramLanguageModels,
 		quadrigramLanguageModels:      &quadrigramLanguageModels,
 		fivegramLanguageModels:        &fivegramLanguageModels,
+		ruleWordShares:                defaultRuleWordShares,
+		ngramLengths:                  defaultNgramLengths,
 	}
+	detector.characterLookup = detector.collectCharacterLookup()
+	return detector
 }
 
 var detectorForEnglishAndGerman = newDetectorForEnglishAndGerman()
@@ -236,7 +242,8 @@ func TestLookUpNgramProbability(t *testing.T) {
 		{German, "alter", 0.3},
 	}
 	for _, testCase := range testCases {
-		probability := detectorForEnglishAndGerman.lookUpNgramProbability(testCase.language, newNgram(testCase.ngram))
+		probability, err := detectorForEnglishAndGerman.lookUpNgramProbability(testCase.language, newNgram(testCase.ngram))
+		assert.NoError(t, err)
 		message := fmt.Sprintf(
 			"expected probability %v for language %v and ngram '%s', got %v",
 			testCase.expectedProbability,
@@ -273,7 +280,8 @@ func TestComputeSumOfNgramProbabilities(t *testing.T) {
 		},
 	}
 	for _, testCase := range testCases {
-		sumOfProbabilities := detectorForEnglishAndGerman.computeSumOfNgramProbabilities(English, testCase.ngramModel)
+		sumOfProbabilities, err := detectorForEnglishAndGerman.computeSumOfNgramProbabilities(English, testCase.ngramModel)
+		assert.NoError(t, err)
 		message := fmt.Sprintf(
 			"expected sum %v for language %v and ngrams %v, got %v",
 			testCase.expectedSumOfProbabilities,
@@ -329,6 +337,49 @@ func TestComputeLanguageProbabilities(t *testing.T) {
 	}
 }
 
+func TestComputeLanguageProbabilitiesWithUnseenNgramPenalty(t *testing.T) {
+	detector := newDetectorForEnglishAndGerman()
+	detector.unseenNgramLogProbability = math.Log(0.01)
+	indexedDetector := newIndexedDetector(detector)
+
+	testCases := []struct {
+		ngramModel            testDataLanguageModel
+		expectedProbabilities map[Language]float64
+	}{
+		{
+			testDataModel([][]string{{"a"}, {"l"}, {"t"}, {"e"}, {"r"}}),
+			map[Language]float64{
+				English: math.Log(0.01) + math.Log(0.02) + math.Log(0.03) + math.Log(0.04) + math.Log(0.05),
+				German:  math.Log(0.06) + math.Log(0.07) + math.Log(0.08) + math.Log(0.09) + math.Log(0.1),
+			},
+		},
+		{
+			// unknown Trigram("wxy") falls through to the penalty
+			testDataModel([][]string{{"alt", "al", "a"}, {"lte", "lt", "l"}, {"ter", "te", "t"}, {"wxy", "wx", "w"}}),
+			map[Language]float64{
+				English: math.Log(0.19) + math.Log(0.2) + math.Log(0.21) + math.Log(0.01),
+				German:  math.Log(0.22) + math.Log(0.23) + math.Log(0.24) + math.Log(0.01),
+			},
+		},
+		{
+			// languages without any known ngram are still left out
+			testDataModel([][]string{{"wxy", "wx", "w"}}),
+			map[Language]float64{},
+		},
+	}
+	languages := []Language{English, German}
+	for _, testCase := range testCases {
+		for _, d := range []languageDetector{detector, indexedDetector} {
+			probabilities := d.computeLanguageProbabilities(testCase.ngramModel, languages)
+			assert.Equal(t, len(testCase.expectedProbabilities), len(probabilities))
+
+			for language, expectedProbability := range testCase.expectedProbabilities {
+				assert.InDelta(t, expectedProbability, probabilities[language], delta)
+			}
+		}
+	}
+}
+
 func TestComputeLanguageConfidenceValues(t *testing.T) {
 	testCases := []struct {
 		text                     string
@@ -671,6 +722,18 @@ func TestDetectLanguageWithRules(t *testing.T) {
 		{"துன்பங்களை", Tamil},
 		{"కృష్ణదేవరాయలు", Telugu},
 		{"ในทางหลวงหมายเลข", Thai},
+
+		// words written in a script of their own
+		{"ሰላም", Amharic},
+		{"မြန်မာ", Burmese},
+		{"ދިވެހި", Dhivehi},
+		{"ಕನ್ನಡ", Kannada},
+		{"ភាសាខ្មែរ", Khmer},
+		{"ພາສາລາວ", Lao},
+		{"മലയാളം", Malayalam},
+		{"ଓଡ଼ିଆ", Odia},
+		{"සිංහල", Sinhala},
+		{"བོད", Tibetan},
 	}
 	for _, testCase := range testCases {
 		detectedLanguage := detectorForAllLanguages.detectLanguageWithRules([]string{testCase.word})
@@ -841,6 +904,101 @@ func TestLowAccuracyModeReportsUnknownLanguageForUnigramsAndBigrams(t *testing.T
 	assert.Equal(t, false, exists)
 }
 
+func TestDetectorIsReadyWithoutBackgroundPreloading(t *testing.T) {
+	detector := newLanguageDetector([]Language{English, German}, 0.0, false, false)
+	assert.True(t, detector.IsReady())
+	assert.NoError(t, detector.WaitUntilReady(context.Background()))
+}
+
+func TestDetectorIsReadyAfterBackgroundPreloading(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		WithBackgroundPreloading().
+		Build()
+
+	assert.NoError(t, detector.WaitUntilReady(context.Background()))
+	assert.True(t, detector.IsReady())
+
+	language, exists := detector.DetectLanguageOf("languages are awesome")
+	assert.Equal(t, English, language)
+	assert.True(t, exists)
+}
+
+func TestWaitUntilReadyReturnsContextError(t *testing.T) {
+	detector := newLanguageDetector([]Language{English, German}, 0.0, false, false)
+	detector.readiness = newReadiness()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.False(t, detector.IsReady())
+	assert.ErrorIs(t, detector.WaitUntilReady(ctx), context.Canceled)
+}
+
+func TestSelectivePreloadingOfLanguageModels(t *testing.T) {
+	testCases := []struct {
+		isLowAccuracyModeEnabled bool
+		ngramLengths             []int
+		expectedNgramLengths     []int
+	}{
+		{false, []int{3, 4}, []int{3, 4}},
+		{false, []int{1, 2, 3, 4, 5}, []int{1, 2, 3, 4, 5}},
+		{true, []int{1, 3, 5}, []int{3}},
+	}
+	for _, testCase := range testCases {
+		detector := newLanguageDetector([]Language{English, German, French}, 0.0, false, testCase.isLowAccuracyModeEnabled)
+		detector.unigramLanguageModels = &sync.Map{}
+		detector.bigramLanguageModels = &sync.Map{}
+		detector.trigramLanguageModels = &sync.Map{}
+		detector.quadrigramLanguageModels = &sync.Map{}
+		detector.fivegramLanguageModels = &sync.Map{}
+
+		assert.NoError(t, detector.preloadLanguageModels([]Language{English, German}, testCase.ngramLengths))
+
+		for _, ngramLength := range allNgramLengths() {
+			models := detector.languageModelsOfLength(ngramLength)
+			isExpected := slices.Contains(testCase.expectedNgramLengths, ngramLength)
+
+			for _, language := range []Language{English, German} {
+				_, exists := models.Load(language)
+				message := fmt.Sprintf("unexpected preloading state of %v %ss", language, getNgramNameByLength(ngramLength))
+				assert.Equal(t, isExpected, exists, message)
+			}
+
+			_, exists := models.Load(French)
+			assert.False(t, exists, "French language models must not be preloaded")
+		}
+	}
+}
+
+func TestLanguagesWithoutLanguageModelsAreDetectedByRules(t *testing.T) {
+	hooks := newRecordingHooks()
+	detector := newDetectorWithHooks([]Language{English, Khmer, Sinhala}, 0.0, false, hooks)
+
+	assert.NoError(t, detector.preloadLanguageModels([]Language{Khmer, Sinhala}, allNgramLengths()))
+	assert.Empty(t, hooks.loadedModels)
+
+	for _, testCase := range []struct {
+		text             string
+		expectedLanguage Language
+	}{
+		{"ភាសាខ្មែរ", Khmer},
+		{"භාෂාව සිංහල", Sinhala},
+	} {
+		language, reason := detector.detectLanguageOf(testCase.text)
+		assert.Equal(t, testCase.expectedLanguage, language)
+		assert.Equal(t, ReasonRules, reason)
+		assert.Equal(t, 1.0, detector.ComputeLanguageConfidence(testCase.text, testCase.expectedLanguage))
+	}
+
+	for _, language := range []Language{Khmer, Sinhala} {
+		for _, ngramLength := range allNgramLengths() {
+			_, exists := detector.languageModelsOfLength(ngramLength).Load(language)
+			assert.False(t, exists, "%v must not have any language models", language)
+		}
+	}
+}
+
 func BenchmarkLanguageDetectionInLowAccuracyMode(b *testing.B) {
 	detector := newLanguageDetector(AllLanguages(), 0.0, true, true)
 	sentences := []string{
@@ -885,6 +1043,94 @@ func BenchmarkLanguageDetectionInHighAccuracyMode(b *testing.B) {
 	}
 }
 
+var textsForNgramIndexBenchmarks = []string{
+	"It has three co-chairs, one from each of a provincial health and agriculture department, and a third from the federal government.",
+	"Ich spreche Französisch nur ein bisschen.",
+	"languages are awesome",
+	"Parlez-vous français?",
+	"И потому я должен возблагодарить провидение.",
+}
+
+func BenchmarkLanguageDetectionWithoutInvertedNgramIndex(b *testing.B) {
+	detector := NewLanguageDetectorBuilder().
+		FromAllLanguages().
+		WithPreloadedLanguageModels().
+		Build()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, text := range textsForNgramIndexBenchmarks {
+			detector.DetectLanguageOf(text)
+		}
+	}
+}
+
+func BenchmarkLanguageDetectionWithInvertedNgramIndex(b *testing.B) {
+	detector := NewLanguageDetectorBuilder().
+		FromAllLanguages().
+		WithPreloadedLanguageModels().
+		WithInvertedNgramIndex().
+		Build()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, text := range textsForNgramIndexBenchmarks {
+			detector.DetectLanguageOf(text)
+		}
+	}
+}
+
+func BenchmarkLanguageDetectionWithQuantizedLanguageModels(b *testing.B) {
+	detector := NewLanguageDetectorBuilder().
+		FromAllLanguages().
+		WithPreloadedLanguageModels().
+		WithQuantizedLanguageModels(16).
+		Build()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, text := range textsForNgramIndexBenchmarks {
+			detector.DetectLanguageOf(text)
+		}
+	}
+}
+
+var shortTextsForRuleBenchmarks = []string{
+	"Mit freundlichen Grüßen",
+	"languages are awesome",
+	"Příliš žluťoučký kůň",
+	"Это неправильно",
+	"Η Ελλάδα είναι όμορφη",
+	"東京都に住んでいます",
+	"我们必须面对问题",
+	"नमस्ते दुनिया",
+	"안녕하세요 여러분",
+	"ภาษาไทยง่ายนิดเดียว",
+}
+
+func BenchmarkDetectLanguageWithRules(b *testing.B) {
+	wordsOfTexts := make([][]string, len(shortTextsForRuleBenchmarks))
+	for i, text := range shortTextsForRuleBenchmarks {
+		wordsOfTexts[i] = splitTextIntoWords(text)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, words := range wordsOfTexts {
+			detectorForAllLanguages.detectLanguageWithRules(words)
+		}
+	}
+}
+
+func BenchmarkFilterLanguagesByRules(b *testing.B) {
+	wordsOfTexts := make([][]string, len(shortTextsForRuleBenchmarks))
+	for i, text := range shortTextsForRuleBenchmarks {
+		wordsOfTexts[i] = splitTextIntoWords(text)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, words := range wordsOfTexts {
+			detectorForAllLanguages.filterLanguagesByRules(words)
+		}
+	}
+}
+
 func roundToTwoDecimalPlaces(value float64) float64 {
 	return math.Round(value*100) / 100
 }
@@ -0,0 +1,92 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import "math"
+
+// RelativeDistancePoint is a point of a curve which maps the number of
+// characters of a text to the minimum relative distance that the confidence
+// values of the two most likely languages have to satisfy. Curves are
+// configured with LanguageDetectorBuilder.WithMinimumRelativeDistanceCurve
+// and LanguageDetectorBuilder.WithLanguageMinimumRelativeDistanceCurve.
+type RelativeDistancePoint struct {
+	// CharacterCount is the number of letters of the text.
+	CharacterCount int
+
+	// Distance is the minimum relative distance for texts of this length.
+	Distance float64
+}
+
+func validateRelativeDistanceCurve(points []RelativeDistancePoint) {
+	if len(points) == 0 {
+		panic("Minimum relative distance curve must contain at least one point")
+	}
+	for i, point := range points {
+		if point.Distance < 0.0 || point.Distance > 0.99 {
+			panic("Minimum relative distance must lie in between 0.0 and 0.99")
+		}
+		if point.CharacterCount < 0 || (i > 0 && point.CharacterCount <= points[i-1].CharacterCount) {
+			panic("Character counts of the minimum relative distance curve must be non-negative and strictly increasing")
+		}
+	}
+}
+
+// relativeDistanceAt interpolates the given curve linearly at the given
+// character count. Below the first and above the last point, the distance
+// of the respective point is returned.
+func relativeDistanceAt(points []RelativeDistancePoint, characterCount int) float64 {
+	if characterCount <= points[0].CharacterCount {
+		return points[0].Distance
+	}
+	for i := 1; i < len(points); i++ {
+		if characterCount <= points[i].CharacterCount {
+			lower, upper := points[i-1], points[i]
+			share := float64(characterCount-lower.CharacterCount) / float64(upper.CharacterCount-lower.CharacterCount)
+			return lower.Distance + share*(upper.Distance-lower.Distance)
+		}
+	}
+	return points[len(points)-1].Distance
+}
+
+// minimumRelativeDistanceOf returns the minimum relative distance which the
+// given most likely languages have to satisfy for the given text. If any of
+// both languages has a curve of its own, the larger of their distances is
+// used instead of the general one.
+func (detector languageDetector) minimumRelativeDistanceOf(
+	text string,
+	mostLikelyLanguage Language,
+	secondMostLikelyLanguage Language,
+) float64 {
+	if detector.minimumRelativeDistanceCurve == nil && detector.languageDistanceCurves == nil {
+		return detector.minimumRelativeDistance
+	}
+	characterCount := countCharacters(splitTextIntoWords(text))
+
+	distance := math.Inf(-1)
+	for _, language := range []Language{mostLikelyLanguage, secondMostLikelyLanguage} {
+		if points, exists := detector.languageDistanceCurves[language]; exists {
+			distance = math.Max(distance, relativeDistanceAt(points, characterCount))
+		}
+	}
+	if !math.IsInf(distance, -1) {
+		return distance
+	}
+	if detector.minimumRelativeDistanceCurve != nil {
+		return relativeDistanceAt(detector.minimumRelativeDistanceCurve, characterCount)
+	}
+	return detector.minimumRelativeDistance
+}
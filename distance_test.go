@@ -0,0 +1,89 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRelativeDistanceAt(t *testing.T) {
+	points := []RelativeDistancePoint{{10, 0.5}, {20, 0.3}, {60, 0.1}}
+
+	testCases := []struct {
+		characterCount   int
+		expectedDistance float64
+	}{
+		{0, 0.5},
+		{10, 0.5},
+		{15, 0.4},
+		{20, 0.3},
+		{40, 0.2},
+		{60, 0.1},
+		{500, 0.1},
+	}
+	for _, testCase := range testCases {
+		assert.InDelta(t, testCase.expectedDistance, relativeDistanceAt(points, testCase.characterCount), 1e-9)
+	}
+
+	assert.Equal(t, 0.25, relativeDistanceAt([]RelativeDistancePoint{{0, 0.25}}, 100))
+}
+
+func TestMinimumRelativeDistanceCurve(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German).
+		WithMinimumRelativeDistanceCurve(RelativeDistancePoint{0, 0.9}, RelativeDistancePoint{30, 0.5}).
+		Build()
+
+	testCases := []struct {
+		text             string
+		expectedLanguage Language
+	}{
+		{"bank", Unknown},
+		{"Das Wetter ist heute sehr schön", German},
+		{"The quick brown fox jumps over the lazy dog", English},
+	}
+	for _, testCase := range testCases {
+		language, _ := detector.DetectLanguageOf(testCase.text)
+		assert.Equal(t, testCase.expectedLanguage, language, testCase.text)
+	}
+}
+
+func TestLanguageMinimumRelativeDistanceCurve(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German).
+		WithLanguageMinimumRelativeDistanceCurve(French, RelativeDistancePoint{0, 0.7}).
+		Build()
+
+	language, _ := detector.DetectLanguageOf("prologue")
+	assert.Equal(t, Unknown, language)
+
+	language, _ = detector.DetectLanguageOf("bank")
+	assert.Equal(t, German, language)
+
+	// The curve of the second most likely language applies as well.
+	detector = NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German).
+		WithLanguageMinimumRelativeDistanceCurve(English, RelativeDistancePoint{0, 0.4}).
+		Build()
+
+	language, _ = detector.DetectLanguageOf("bank")
+	assert.Equal(t, Unknown, language)
+
+	language, _ = detector.DetectLanguageOf("Das Wetter ist heute sehr schön")
+	assert.Equal(t, German, language)
+}
@@ -0,0 +1,231 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"fmt"
+	"golang.org/x/exp/slices"
+)
+
+// CharacterEvidence is an entry of a character evidence table. It maps a set
+// of characters to the languages whose texts may contain them.
+//
+// The rule engine uses these tables to narrow down the set of possible
+// languages before the language models are consulted. If the characters are
+// marked as unique, they occur in a single language only, so that a text
+// consisting mostly of words with these characters is identified as this
+// language right away.
+type CharacterEvidence struct {
+	// Characters is the set of characters, such as "Ææ". Both lowercase
+	// and uppercase variants should be given.
+	Characters string
+
+	// Languages are the languages whose texts may contain the characters.
+	Languages []Language
+
+	// IsUnique marks the characters as unique to the only language in Languages.
+	IsUnique bool
+}
+
+// characterEvidence holds the character evidence tables of a detector
+// which has been configured with custom tables.
+type characterEvidence struct {
+	uniqueCharacters map[Language]string
+	charsToLanguages map[string][]Language
+}
+
+func newCharacterEvidence(
+	languages []Language,
+	table []CharacterEvidence,
+	isBuiltInEvidenceReplaced bool,
+) *characterEvidence {
+	evidence := &characterEvidence{
+		uniqueCharacters: make(map[Language]string),
+		charsToLanguages: make(map[string][]Language),
+	}
+
+	if !isBuiltInEvidenceReplaced {
+		for _, language := range languages {
+			if characters := language.uniqueCharacters(); len(characters) > 0 {
+				evidence.uniqueCharacters[language] = characters
+			}
+		}
+		for characters, mappedLanguages := range charsToLanguagesMapping {
+			evidence.charsToLanguages[characters] = mappedLanguages
+		}
+	}
+
+	for _, entry := range table {
+		if entry.IsUnique {
+			evidence.uniqueCharacters[entry.Languages[0]] += entry.Characters
+		} else {
+			mappedLanguages := slices.Clone(evidence.charsToLanguages[entry.Characters])
+			for _, language := range entry.Languages {
+				if !slices.Contains(mappedLanguages, language) {
+					mappedLanguages = append(mappedLanguages, language)
+				}
+			}
+			evidence.charsToLanguages[entry.Characters] = mappedLanguages
+		}
+	}
+
+	return evidence
+}
+
+func validateCharacterEvidence(languages []Language, table []CharacterEvidence) {
+	for _, entry := range table {
+		if len(entry.Characters) == 0 {
+			panic("Character evidence must contain at least one character")
+		}
+		if len(entry.Languages) == 0 {
+			panic(fmt.Sprintf("Character evidence '%s' must refer to at least one language", entry.Characters))
+		}
+		if entry.IsUnique && len(entry.Languages) != 1 {
+			panic(fmt.Sprintf("Characters '%s' can only be unique to exactly one language", entry.Characters))
+		}
+		for _, language := range entry.Languages {
+			if !slices.Contains(languages, language) {
+				panic(fmt.Sprintf(
+					"Language %v of character evidence '%s' is not among the languages of the detector",
+					language,
+					entry.Characters,
+				))
+			}
+		}
+	}
+}
+
+// characterLookup maps single characters to the languages of the character
+// evidence tables containing them, so that the rule engine does not have to
+// search the tables for every character of the input text.
+type characterLookup struct {
+	uniqueCharacterLanguages map[rune][]Language
+	characterLanguages       map[rune][]Language
+}
+
+func (detector languageDetector) collectCharacterLookup() *characterLookup {
+	lookup := &characterLookup{
+		uniqueCharacterLanguages: make(map[rune][]Language),
+		characterLanguages:       make(map[rune][]Language),
+	}
+	for _, language := range detector.languagesWithUniqueCharacters {
+		for _, chr := range detector.uniqueCharactersOf(language) {
+			if !slices.Contains(lookup.uniqueCharacterLanguages[chr], language) {
+				lookup.uniqueCharacterLanguages[chr] = append(lookup.uniqueCharacterLanguages[chr], language)
+			}
+		}
+	}
+	for characters, languages := range detector.charsToLanguages() {
+		for _, chr := range characters {
+			lookup.characterLanguages[chr] = append(lookup.characterLanguages[chr], languages...)
+		}
+	}
+	for _, language := range detector.languages {
+		if !detector.usesScriptVariantModels(language) {
+			continue
+		}
+		for _, variant := range language.Variants() {
+			for _, chr := range variant.sharedCharacters() {
+				if !slices.Contains(lookup.characterLanguages[chr], language) {
+					lookup.characterLanguages[chr] = append(lookup.characterLanguages[chr], language)
+				}
+			}
+		}
+	}
+	return lookup
+}
+
+func (detector languageDetector) uniqueCharactersOf(language Language) string {
+	if detector.characterEvidence == nil {
+		return language.uniqueCharacters()
+	}
+	return detector.characterEvidence.uniqueCharacters[language]
+}
+
+func (detector languageDetector) charsToLanguages() map[string][]Language {
+	if detector.characterEvidence == nil {
+		return charsToLanguagesMapping
+	}
+	return detector.characterEvidence.charsToLanguages
+}
+
+// builtInCharacterLookup is the character lookup of the built-in character
+// evidence tables for all supported languages.
+var builtInCharacterLookup = languageDetector{
+	languagesWithUniqueCharacters: collectLanguagesWithUniqueCharacters(AllLanguages()),
+}.collectCharacterLookup()
+
+// LanguagesUsingCharacter returns the languages whose texts may contain the
+// given character according to the built-in rules, sorted alphabetically.
+//
+// If the character is unique to a language, only this language is returned.
+// Otherwise, the languages of the character evidence tables are returned if
+// the character is listed there, or else all languages written in the
+// alphabet which the character belongs to. An empty slice is returned for
+// characters which do not belong to any supported alphabet, such as digits.
+func LanguagesUsingCharacter(chr rune) []Language {
+	return builtInCharacterLookup.languagesUsing(chr, AllLanguages())
+}
+
+// IsUniqueCharacter returns the language which the given character is
+// unique to according to the built-in rules, such as Hungarian for ő.
+// If the character is used by several languages or by none, the second
+// return value is false.
+func IsUniqueCharacter(chr rune) (Language, bool) {
+	return uniqueLanguageOf(LanguagesUsingCharacter(chr))
+}
+
+func (detector languageDetector) LanguagesUsingCharacter(chr rune) []Language {
+	lookup := detector.characterLookup
+	if detector.characterEvidence == nil {
+		lookup = builtInCharacterLookup
+	}
+	return lookup.languagesUsing(chr, detector.languages)
+}
+
+func (detector languageDetector) IsUniqueCharacter(chr rune) (Language, bool) {
+	return uniqueLanguageOf(detector.LanguagesUsingCharacter(chr))
+}
+
+// languagesUsing returns those of the given languages which the character
+// is used in, taking the most specific evidence available for it.
+func (lookup *characterLookup) languagesUsing(chr rune, languages []Language) []Language {
+	candidates := lookup.uniqueCharacterLanguages[chr]
+	if len(candidates) == 0 {
+		candidates = lookup.characterLanguages[chr]
+	}
+	if len(candidates) == 0 {
+		if alphabet, exists := alphabetOf(chr); exists {
+			candidates = alphabet.supportedLanguages()
+		}
+	}
+	languagesUsingCharacter := make([]Language, 0)
+	for _, language := range candidates {
+		if slices.Contains(languages, language) && !slices.Contains(languagesUsingCharacter, language) {
+			languagesUsingCharacter = append(languagesUsingCharacter, language)
+		}
+	}
+	slices.Sort(languagesUsingCharacter)
+	return languagesUsingCharacter
+}
+
+func uniqueLanguageOf(languages []Language) (Language, bool) {
+	if len(languages) == 1 {
+		return languages[0], true
+	}
+	return Unknown, false
+}
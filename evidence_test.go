@@ -0,0 +1,127 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewCharacterEvidence(t *testing.T) {
+	languages := []Language{English, French, German}
+	table := []CharacterEvidence{
+		{"Ŷŷ", []Language{French}, true},
+		{"Ææ", []Language{English, French}, false},
+		{"Ää", []Language{English}, false},
+	}
+
+	evidence := newCharacterEvidence(languages, table, false)
+	assert.Equal(t, map[Language]string{French: "Ŷŷ", German: "ß"}, evidence.uniqueCharacters)
+	assert.Equal(t, []Language{Bokmal, Danish, Icelandic, Nynorsk, English, French}, evidence.charsToLanguages["Ææ"])
+	assert.Equal(t, []Language{Estonian, Finnish, German, Slovak, Swedish, English}, evidence.charsToLanguages["Ää"])
+	assert.Equal(t, []Language{Estonian, Finnish, German, Slovak, Swedish}, charsToLanguagesMapping["Ää"])
+
+	evidence = newCharacterEvidence(languages, table, true)
+	assert.Equal(t, map[Language]string{French: "Ŷŷ"}, evidence.uniqueCharacters)
+	assert.Equal(
+		t,
+		map[string][]Language{"Ææ": {English, French}, "Ää": {English}},
+		evidence.charsToLanguages,
+	)
+}
+
+func TestDetectionWithAdditionalCharacterEvidence(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German).
+		WithCharacterEvidence(
+			CharacterEvidence{"Ŷŷ", []Language{French}, true},
+			CharacterEvidence{"Ææ", []Language{English, French}, false},
+		).
+		Build().(languageDetector)
+
+	assert.Equal(t, French, detector.detectLanguageWithRules([]string{"cŷ"}))
+	assert.Equal(t, German, detector.detectLanguageWithRules([]string{"straße"}))
+	assert.ElementsMatch(t, []Language{English, French}, detector.filterLanguagesByRules([]string{"æsthetic"}))
+
+	language, exists := detector.DetectLanguageOf("cŷ")
+	assert.Equal(t, French, language)
+	assert.True(t, exists)
+}
+
+func TestDetectionWithReplacedCharacterEvidence(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German).
+		WithReplacedCharacterEvidence().
+		Build().(languageDetector)
+
+	assert.Equal(t, Unknown, detector.detectLanguageWithRules([]string{"straße"}))
+	assert.ElementsMatch(t, []Language{English, French, German}, detector.filterLanguagesByRules([]string{"über"}))
+}
+
+func TestLanguagesUsingCharacter(t *testing.T) {
+	testCases := []struct {
+		chr               rune
+		expectedLanguages []Language
+	}{
+		{'ő', []Language{Hungarian}},
+		{'ß', []Language{German}},
+		{'α', []Language{Greek}},
+		{'ぁ', []Language{Japanese}},
+		{'漢', []Language{Chinese, Japanese}},
+		{'ø', []Language{Bokmal, Danish, Nynorsk}},
+		{'1', []Language{}},
+		{'!', []Language{}},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedLanguages, LanguagesUsingCharacter(testCase.chr), "character: %c", testCase.chr)
+	}
+	assert.Contains(t, LanguagesUsingCharacter('a'), English)
+	assert.Contains(t, LanguagesUsingCharacter('a'), Yoruba)
+}
+
+func TestIsUniqueCharacter(t *testing.T) {
+	language, isUnique := IsUniqueCharacter('ő')
+	assert.Equal(t, Hungarian, language)
+	assert.True(t, isUnique)
+
+	language, isUnique = IsUniqueCharacter('a')
+	assert.Equal(t, Unknown, language)
+	assert.False(t, isUnique)
+}
+
+func TestLanguagesUsingCharacterOfDetector(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, French, Hungarian).
+		Build()
+
+	assert.Equal(t, []Language{Hungarian}, detector.LanguagesUsingCharacter('ő'))
+	assert.Equal(t, []Language{}, detector.LanguagesUsingCharacter('ß'))
+	assert.Equal(t, []Language{English, French, Hungarian}, detector.LanguagesUsingCharacter('a'))
+
+	language, isUnique := detector.IsUniqueCharacter('ő')
+	assert.Equal(t, Hungarian, language)
+	assert.True(t, isUnique)
+
+	detector = NewLanguageDetectorBuilder().
+		FromLanguages(English, French, Hungarian).
+		WithCharacterEvidence(CharacterEvidence{"Ŷŷ", []Language{French}, true}).
+		Build()
+
+	language, isUnique = detector.IsUniqueCharacter('ŷ')
+	assert.Equal(t, French, language)
+	assert.True(t, isUnique)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"strings"
+)
+
+//go:embed function-words
+var functionWordFiles embed.FS
+
+// collectFunctionWords loads the lists of very frequent function words, such
+// as articles, pronouns and prepositions, of the given languages. Languages
+// without a list are left out. Lists are only shipped for languages which
+// share their alphabet with other languages, as all others are identified
+// by their alphabet already.
+func collectFunctionWords(languages []Language) map[Language]map[string]struct{} {
+	functionWords := make(map[Language]map[string]struct{})
+	for _, language := range languages {
+		isoCode := strings.ToLower(language.IsoCode639_1().String())
+		file, err := functionWordFiles.Open(fmt.Sprintf("function-words/%s.txt", isoCode))
+		if err != nil {
+			continue
+		}
+		words := make(map[string]struct{})
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if word := strings.TrimSpace(scanner.Text()); len(word) > 0 {
+				words[word] = struct{}{}
+			}
+		}
+		file.Close()
+		functionWords[language] = words
+	}
+	return functionWords
+}
+
+// filterLanguagesByFunctionWords narrows down the candidate languages of short
+// texts to those languages whose function words make up at least the share of
+// the text's words given by RuleWordShares.Characters, similar to how
+// filterLanguagesByCharacters evaluates characters.
+// Languages without a list of function words cannot be ruled out this way,
+// so they remain candidates. Texts of at least NgramLengths.LongTextThreshold
+// characters are considered long enough to be classified reliably by the
+// language models alone, so function words are not consulted for them.
+func (detector languageDetector) filterLanguagesByFunctionWords(words []string, candidates []Language) []Language {
+	if detector.functionWords == nil {
+		return candidates
+	}
+
+	if countCharacters(words) >= detector.ngramLengths.LongTextThreshold {
+		return candidates
+	}
+
+	languageCounts := make(map[Language]uint32)
+	for _, word := range words {
+		for _, language := range candidates {
+			if _, exists := detector.functionWords[language][word]; exists {
+				languageCounts[language]++
+			}
+		}
+	}
+
+	minimumCount := float64(len(words)) * detector.ruleWordShares.Characters
+	var languageSubset []Language
+	isSubsetFound := false
+
+	for _, language := range candidates {
+		if _, hasFunctionWords := detector.functionWords[language]; !hasFunctionWords {
+			languageSubset = append(languageSubset, language)
+		} else if count := languageCounts[language]; count > 0 && float64(count) >= minimumCount {
+			languageSubset = append(languageSubset, language)
+			isSubsetFound = true
+		}
+	}
+
+	if isSubsetFound {
+		return languageSubset
+	}
+
+	return candidates
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCollectFunctionWords(t *testing.T) {
+	functionWords := collectFunctionWords([]Language{English, German, Japanese})
+
+	assert.Len(t, functionWords, 2)
+	assert.Contains(t, functionWords[English], "the")
+	assert.Contains(t, functionWords[German], "und")
+	assert.NotContains(t, functionWords, Japanese)
+}
+
+func TestFilterLanguagesByFunctionWords(t *testing.T) {
+	detector := newLanguageDetector([]Language{English, French, German, Spanish}, 0.0, false, false)
+	detector.functionWords = collectFunctionWords(detector.languages)
+
+	testCases := []struct {
+		words              []string
+		expectedCandidates []Language
+	}{
+		{[]string{"the"}, []Language{English}},
+		{[]string{"the", "house"}, []Language{English}},
+		{[]string{"und", "the"}, []Language{English, German}},
+		{[]string{"kaffeeautomat"}, []Language{English, French, German, Spanish}},
+		{[]string{"kaffeeautomat", "kaputt", "und"}, []Language{English, French, German, Spanish}},
+		{[]string{strings.Repeat("a", defaultNgramLengths.LongTextThreshold), "the"}, []Language{English, French, German, Spanish}},
+	}
+	for _, testCase := range testCases {
+		candidates := detector.filterLanguagesByFunctionWords(testCase.words, detector.languages)
+		assert.Equal(t, testCase.expectedCandidates, candidates, "words: %v", testCase.words)
+	}
+}
+
+func TestFilterLanguagesByFunctionWordsIsDisabledByDefault(t *testing.T) {
+	detector := newLanguageDetector([]Language{English, French, German, Spanish}, 0.0, false, false)
+
+	candidates := detector.filterLanguagesByFunctionWords([]string{"the"}, detector.languages)
+
+	assert.Equal(t, []Language{English, French, German, Spanish}, candidates)
+}
+
+func TestDetectLanguageWithFunctionWords(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German, Spanish).
+		WithFunctionWords().
+		Build()
+
+	language, exists := detector.DetectLanguageOf("und")
+	assert.Equal(t, German, language)
+	assert.True(t, exists)
+	assert.Equal(t, 1.0, detector.ComputeLanguageConfidence("und", German))
+}
+
+func TestFilterLanguagesByFunctionWordsKeepsLanguagesWithoutFunctionWords(t *testing.T) {
+	detector := newLanguageDetector([]Language{English, German, Maori}, 0.0, false, false)
+	detector.functionWords = collectFunctionWords(detector.languages)
+
+	assert.Equal(t, []Language{English, Maori}, detector.filterLanguagesByFunctionWords([]string{"the"}, detector.languages))
+	assert.Equal(t, []Language{English, German, Maori}, detector.filterLanguagesByFunctionWords([]string{"whare"}, detector.languages))
+}
+
+func TestFilterLanguagesByFunctionWordsFollowsLongTextThreshold(t *testing.T) {
+	detector := newLanguageDetector([]Language{English, French, German, Spanish}, 0.0, false, false)
+	detector.functionWords = collectFunctionWords(detector.languages)
+	detector.ngramLengths = NgramLengths{Short: allNgramLengths(), Long: []int{3}, LongTextThreshold: 10}
+
+	assert.Equal(
+		t,
+		[]Language{English},
+		detector.filterLanguagesByFunctionWords([]string{"the", "of"}, detector.languages),
+	)
+	assert.Equal(
+		t,
+		[]Language{English, French, German, Spanish},
+		detector.filterLanguagesByFunctionWords([]string{"the", "of", "the", "of", "the"}, detector.languages),
+	)
+}
+
+func TestFunctionWordsOnlyCollideWhereTheyAreFunctionWordsInBothLanguages(t *testing.T) {
+	testCases := []struct {
+		first               Language
+		second              Language
+		expectedSharedWords []string
+	}{
+		{English, Danish, []string{"at", "for", "her", "i", "over"}},
+		{English, Dutch, []string{"in", "is", "over", "we"}},
+		{English, German, []string{"am", "an", "in", "so", "was", "will"}},
+		{English, Romanian, []string{"a"}},
+		{English, Swedish, nil},
+		{Spanish, French, []string{"a", "de", "en", "entre", "la", "le", "les", "me", "nos", "que", "si", "te", "tu", "un", "vos"}},
+	}
+	functionWords := collectFunctionWords(AllLanguages())
+	for _, testCase := range testCases {
+		var sharedWords []string
+		for word := range functionWords[testCase.first] {
+			if _, exists := functionWords[testCase.second][word]; exists {
+				sharedWords = append(sharedWords, word)
+			}
+		}
+		sort.Strings(sharedWords)
+		assert.Equal(
+			t,
+			testCase.expectedSharedWords,
+			sharedWords,
+			"function words shared by %v and %v",
+			testCase.first,
+			testCase.second,
+		)
+	}
+}
+
+func TestFilterLanguagesByFunctionWordsFollowsRuleWordShares(t *testing.T) {
+	detector := newLanguageDetector([]Language{English, French, German, Spanish}, 0.0, false, false)
+	detector.functionWords = collectFunctionWords(detector.languages)
+	detector.ruleWordShares = RuleWordShares{Unknown: 0.5, Characters: 1.0}
+
+	assert.Equal(
+		t,
+		[]Language{English, French, German, Spanish},
+		detector.filterLanguagesByFunctionWords([]string{"und", "the"}, detector.languages),
+	)
+	assert.Equal(
+		t,
+		[]Language{English},
+		detector.filterLanguagesByFunctionWords([]string{"of", "the"}, detector.languages),
+	)
+}
+
+func TestLanguagesWithoutFunctionWords(t *testing.T) {
+	languagesWithoutFunctionWords := []Language{
+		Chinese, Ganda, Hindi, Maori, Marathi, Shona, Somali, Sotho, Tsonga, Tswana, Xhosa, Yoruba, Zulu,
+	}
+	functionWords := collectFunctionWords(AllLanguages())
+
+	assert.Len(t, functionWords, 50)
+	for _, language := range languagesWithoutFunctionWords {
+		assert.NotContains(t, functionWords, language)
+	}
+}
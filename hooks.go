@@ -0,0 +1,118 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import "time"
+
+// LanguageDetectorHooks is the interface describing callbacks which are
+// invoked by a LanguageDetector in order to observe language model loading
+// and language detection, for instance to feed metrics or tracing systems.
+//
+// The callbacks are invoked synchronously and possibly concurrently from
+// several goroutines, so implementations must be safe for concurrent use
+// and should return quickly.
+type LanguageDetectorHooks interface {
+	// OnModelLoaded is called after the language model of the given language
+	// and ngram length has been loaded into memory. The duration is the time
+	// it took to load the language model, and ngramCount is the number of
+	// ngrams it contains.
+	OnModelLoaded(language Language, ngramLength int, duration time.Duration, ngramCount int)
+
+	// OnModelEvicted is called after the language model of the given language
+	// and ngram length has been removed from memory by
+	// LanguageDetector.UnloadLanguageModels.
+	OnModelEvicted(language Language, ngramLength int)
+
+	// OnModelLoadFailed is called if the language model of the given language
+	// and ngram length cannot be loaded, for instance because it is corrupt.
+	// The error is a *LanguageModelError. The failure is remembered in place of
+	// the language model, so the callback is invoked only once and the language
+	// is left out of language detection without loading it again. Calling
+	// LanguageDetector.UnloadLanguageModels discards the failure, so that
+	// loading is attempted again afterwards.
+	OnModelLoadFailed(language Language, ngramLength int, err error)
+
+	// OnDetection is called after each invocation of
+	// LanguageDetector.DetectLanguageOf, LanguageDetector.ComputeLanguageConfidenceValues,
+	// LanguageDetector.ComputeLanguageConfidence, LanguageDetector.ComputeUncertainty
+	// and LanguageDetector.DetectMultipleLanguagesOf. The language is the detected
+	// or most likely language, or Unknown if there is none. For
+	// LanguageDetector.DetectMultipleLanguagesOf, it is the language detected for
+	// the text as a whole, and the hook is called once per invocation rather than
+	// once per section. The reason explains how the language has been determined,
+	// and the duration is the time the detection took.
+	OnDetection(language Language, reason DetectionReason, duration time.Duration)
+}
+
+// DetectionReason is the type used for enumerating the reasons why a
+// LanguageDetector has determined a certain language.
+//
+//go:generate stringer -type=DetectionReason
+type DetectionReason int
+
+const (
+	// ReasonNoWords means that the text does not contain any words,
+	// so no language has been detected.
+	ReasonNoWords DetectionReason = iota
+
+	// ReasonRules means that the language has been identified unambiguously
+	// by the rule engine, based on alphabets and characters unique to it.
+	ReasonRules
+
+	// ReasonRuleFilter means that the rule engine has excluded
+	// all languages but one.
+	ReasonRuleFilter
+
+	// ReasonTooFewCharacters means that the text is too short to be
+	// classified in low accuracy mode, so no language has been detected.
+	ReasonTooFewCharacters
+
+	// ReasonNgramModels means that the language has been detected
+	// by evaluating the ngram language models.
+	ReasonNgramModels
+
+	// ReasonNoNgramMatches means that none of the text's ngrams have been
+	// found in the language models, so no language has been detected.
+	ReasonNoNgramMatches
+
+	// ReasonAmbiguousResult means that the most likely languages are too
+	// close to each other with respect to the minimum relative distance,
+	// so no language has been detected.
+	ReasonAmbiguousResult
+
+	// ReasonUnsupportedLanguage means that the text fits none of the
+	// languages of the detector according to the thresholds of open-set
+	// detection, so no language has been detected.
+	ReasonUnsupportedLanguage
+)
+
+func (detector languageDetector) notifyDetection(
+	language Language,
+	reason DetectionReason,
+	startTime time.Time,
+) {
+	if detector.hooks != nil {
+		detector.hooks.OnDetection(language, reason, time.Since(startTime))
+	}
+}
+
+func mostLikelyLanguage(confidenceValues []ConfidenceValue) Language {
+	if len(confidenceValues) == 0 || confidenceValues[0].Value() == 0 {
+		return Unknown
+	}
+	return confidenceValues[0].Language()
+}
@@ -0,0 +1,219 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type modelEvent struct {
+	language    Language
+	ngramLength int
+}
+
+type detectionEvent struct {
+	language Language
+	reason   DetectionReason
+}
+
+type recordingHooks struct {
+	mutex           sync.Mutex
+	loadedModels    []modelEvent
+	ngramCounts     map[modelEvent]int
+	evictedModels   []modelEvent
+	failedModels    []modelEvent
+	detectionEvents []detectionEvent
+}
+
+func newRecordingHooks() *recordingHooks {
+	return &recordingHooks{ngramCounts: make(map[modelEvent]int)}
+}
+
+func (hooks *recordingHooks) OnModelLoaded(language Language, ngramLength int, duration time.Duration, ngramCount int) {
+	hooks.mutex.Lock()
+	defer hooks.mutex.Unlock()
+	event := modelEvent{language, ngramLength}
+	hooks.loadedModels = append(hooks.loadedModels, event)
+	hooks.ngramCounts[event] = ngramCount
+}
+
+func (hooks *recordingHooks) OnModelEvicted(language Language, ngramLength int) {
+	hooks.mutex.Lock()
+	defer hooks.mutex.Unlock()
+	hooks.evictedModels = append(hooks.evictedModels, modelEvent{language, ngramLength})
+}
+
+func (hooks *recordingHooks) OnModelLoadFailed(language Language, ngramLength int, err error) {
+	hooks.mutex.Lock()
+	defer hooks.mutex.Unlock()
+	hooks.failedModels = append(hooks.failedModels, modelEvent{language, ngramLength})
+}
+
+func (hooks *recordingHooks) OnDetection(language Language, reason DetectionReason, duration time.Duration) {
+	hooks.mutex.Lock()
+	defer hooks.mutex.Unlock()
+	hooks.detectionEvents = append(hooks.detectionEvents, detectionEvent{language, reason})
+}
+
+func newDetectorWithHooks(
+	languages []Language,
+	minimumRelativeDistance float64,
+	isLowAccuracyModeEnabled bool,
+	hooks LanguageDetectorHooks,
+) languageDetector {
+	detector := newLanguageDetector(languages, minimumRelativeDistance, false, isLowAccuracyModeEnabled)
+	detector.unigramLanguageModels = &sync.Map{}
+	detector.bigramLanguageModels = &sync.Map{}
+	detector.trigramLanguageModels = &sync.Map{}
+	detector.quadrigramLanguageModels = &sync.Map{}
+	detector.fivegramLanguageModels = &sync.Map{}
+	detector.hooks = hooks
+	return detector
+}
+
+func TestHooksAreInvokedWhenLanguageModelsAreLoadedAndEvicted(t *testing.T) {
+	hooks := newRecordingHooks()
+	detector := newDetectorWithHooks([]Language{English, German}, 0.0, false, hooks)
+
+	detector.DetectLanguageOf("languages are awesome")
+
+	var expectedEvents []modelEvent
+	for _, language := range []Language{English, German} {
+		for _, ngramLength := range allNgramLengths() {
+			expectedEvents = append(expectedEvents, modelEvent{language, ngramLength})
+		}
+	}
+	assert.ElementsMatch(t, expectedEvents, hooks.loadedModels)
+
+	models, _ := detector.trigramLanguageModels.Load(English)
+	assert.Equal(t, len(models.(map[string]float64)), hooks.ngramCounts[modelEvent{English, 3}])
+
+	detector.DetectLanguageOf("languages are awesome")
+	assert.Len(t, hooks.loadedModels, len(expectedEvents), "cached language models must not be reported again")
+
+	detector.UnloadLanguageModels()
+	assert.ElementsMatch(t, expectedEvents, hooks.evictedModels)
+
+	_, exists := detector.trigramLanguageModels.Load(English)
+	assert.False(t, exists)
+}
+
+func TestHooksAreInvokedOnDetection(t *testing.T) {
+	testCases := []struct {
+		text                     string
+		minimumRelativeDistance  float64
+		isLowAccuracyModeEnabled bool
+		expectedLanguage         Language
+		expectedReason           DetectionReason
+	}{
+		{"", 0.0, false, Unknown, ReasonNoWords},
+		{"12345", 0.0, false, Unknown, ReasonNoWords},
+		{"straße", 0.0, false, German, ReasonRules},
+		{"ab", 0.0, true, Unknown, ReasonTooFewCharacters},
+		{"languages are awesome", 0.0, false, English, ReasonNgramModels},
+		{"languages are awesome", 0.99, false, Unknown, ReasonAmbiguousResult},
+	}
+	for _, testCase := range testCases {
+		hooks := newRecordingHooks()
+		detector := newDetectorWithHooks(
+			[]Language{English, German},
+			testCase.minimumRelativeDistance,
+			testCase.isLowAccuracyModeEnabled,
+			hooks,
+		)
+
+		language, _ := detector.DetectLanguageOf(testCase.text)
+
+		assert.Equal(t, testCase.expectedLanguage, language)
+		assert.Equal(
+			t,
+			[]detectionEvent{{testCase.expectedLanguage, testCase.expectedReason}},
+			hooks.detectionEvents,
+			"unexpected detection event for text '%s'",
+			testCase.text,
+		)
+	}
+}
+
+func TestHooksAreInvokedOncePerConfidenceComputation(t *testing.T) {
+	hooks := newRecordingHooks()
+	detector := newDetectorWithHooks([]Language{English, German}, 0.0, false, hooks)
+
+	detector.ComputeLanguageConfidenceValues("languages are awesome")
+	detector.ComputeLanguageConfidence("languages are awesome", German)
+
+	assert.Equal(
+		t,
+		[]detectionEvent{{English, ReasonNgramModels}, {English, ReasonNgramModels}},
+		hooks.detectionEvents,
+	)
+}
+
+func TestLanguagesWithCorruptLanguageModelsAreDroppedAndReported(t *testing.T) {
+	directory := languageModelDirectory(German)
+	checksums, err := loadEmbeddedChecksums(directory)
+	assert.NoError(t, err)
+
+	corruptChecksums := make(map[string]string, len(checksums))
+	for fileName := range checksums {
+		corruptChecksums[fileName] = "0000"
+	}
+	embeddedChecksums.Store(directory, corruptChecksums)
+	t.Cleanup(func() {
+		embeddedChecksums.Store(directory, checksums)
+	})
+
+	hooks := newRecordingHooks()
+	detector := newDetectorWithHooks([]Language{English, German}, 0.0, false, hooks)
+
+	for i := 0; i < 2; i++ {
+		assert.NotPanics(t, func() {
+			language, exists := detector.DetectLanguageOf("languages are awesome")
+			assert.True(t, exists)
+			assert.Equal(t, English, language)
+		})
+	}
+	var expectedFailures []modelEvent
+	for _, ngramLength := range allNgramLengths() {
+		expectedFailures = append(expectedFailures, modelEvent{German, ngramLength})
+	}
+	assert.ElementsMatch(t, expectedFailures, hooks.failedModels)
+
+	_, isFailure := detector.unigramLanguageModels.Load(German)
+	assert.True(t, isFailure)
+	detector.UnloadLanguageModels()
+	_, exists := detector.unigramLanguageModels.Load(German)
+	assert.False(t, exists)
+	assert.NotContains(t, hooks.evictedModels, modelEvent{German, 1})
+}
+
+func TestHooksAreInvokedOnceForDetectionOfMultipleLanguages(t *testing.T) {
+	hooks := newRecordingHooks()
+	detector := newDetectorWithHooks([]Language{English, German}, 0.0, false, hooks)
+
+	detector.DetectMultipleLanguagesOf("")
+	detector.DetectMultipleLanguagesOf("languages are awesome")
+
+	assert.Equal(
+		t,
+		[]detectionEvent{{Unknown, ReasonNoWords}, {English, ReasonNgramModels}},
+		hooks.detectionEvents,
+	)
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"math"
+	"sync"
+)
+
+type ngramIndexEntry struct {
+	language       Language
+	logProbability float64
+}
+
+// ngramIndex maps each ngram of the language models of a detector's languages
+// to the logarithmized probabilities it has in those languages. This allows
+// to score all languages with a single lookup per ngram instead of one lookup
+// per language.
+//
+// The entries are built on the goroutine preloading the language models if
+// background preloading is enabled and are removed again when the language
+// models are unloaded, so they are only accessed under the mutex.
+type ngramIndex struct {
+	mutex   sync.RWMutex
+	entries map[string][]ngramIndexEntry
+}
+
+func (index *ngramIndex) load() map[string][]ngramIndexEntry {
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+	return index.entries
+}
+
+func (index *ngramIndex) store(entries map[string][]ngramIndexEntry) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	index.entries = entries
+}
+
+// buildNgramIndex builds the inverted ngram index from the language models
+// of the given ngram lengths. If one of the language models cannot be loaded,
+// the index is not built and the error is returned.
+func (detector languageDetector) buildNgramIndex(ngramLengths []int) error {
+	entries := make(map[string][]ngramIndexEntry)
+	for _, language := range detector.languages {
+		for _, ngramLength := range ngramLengths {
+			if ngramLength != 3 && detector.isLowAccuracyModeEnabled {
+				continue
+			}
+			err := detector.forEachNgramProbability(language, ngramLength, func(ngram string, probability float64) {
+				if probability > 0 {
+					entries[ngram] = append(entries[ngram], ngramIndexEntry{language, math.Log(probability)})
+				}
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	detector.ngramIndex.store(entries)
+	return nil
+}
+
+func (detector languageDetector) forEachNgramProbability(
+	language Language,
+	ngramLength int,
+	callback func(ngram string, probability float64),
+) error {
+	models := detector.languageModelsOfLength(ngramLength)
+	withScriptVariants := detector.usesScriptVariantModels(language)
+
+	switch detector.quantizationBits {
+	case 8:
+		model, err := loadQuantizedLanguageModels[uint8](models, language, ngramLength, 8, withScriptVariants, detector.hooks)
+		if err != nil {
+			return err
+		}
+		model.forEachNgram(callback)
+	case 16:
+		model, err := loadQuantizedLanguageModels[uint16](models, language, ngramLength, 16, withScriptVariants, detector.hooks)
+		if err != nil {
+			return err
+		}
+		model.forEachNgram(callback)
+	default:
+		model, err := loadLanguageModels(models, language, ngramLength, withScriptVariants, detector.hooks)
+		if err != nil {
+			return err
+		}
+		for ngrm, probability := range model {
+			callback(ngrm, probability)
+		}
+	}
+	return nil
+}
+
+// availableNgramIndexEntries returns the entries of the inverted ngram index,
+// or nil if the index is disabled, has not been built yet or has been removed
+// by unloading the language models. Readiness is checked first, so that the
+// entries are not accessed while they are still being built in the background.
+func (detector languageDetector) availableNgramIndexEntries() map[string][]ngramIndexEntry {
+	if detector.ngramIndex == nil || !detector.IsReady() {
+		return nil
+	}
+	return detector.ngramIndex.load()
+}
+
+func (detector languageDetector) computeLanguageProbabilitiesFromIndex(
+	entries map[string][]ngramIndexEntry,
+	ngramModel testDataLanguageModel,
+	filteredLanguages []Language,
+) map[Language]float64 {
+	isFilteredLanguage := make([]bool, Unknown+1)
+	for _, language := range filteredLanguages {
+		isFilteredLanguage[language] = true
+	}
+
+	sums := make([]float64, Unknown+1)
+	matchedNgramCounts := make([]int, Unknown+1)
+	lastMatchingNgramPosition := make([]int, Unknown+1)
+
+	for i, ngrams := range ngramModel.ngrams {
+		position := i + 1
+		remainingLanguageCount := len(filteredLanguages)
+
+		for _, ngrm := range ngrams {
+			for _, entry := range entries[ngrm.value] {
+				language := entry.language
+				if isFilteredLanguage[language] && lastMatchingNgramPosition[language] != position {
+					sums[language] += entry.logProbability
+					matchedNgramCounts[language]++
+					lastMatchingNgramPosition[language] = position
+					remainingLanguageCount--
+				}
+			}
+			if remainingLanguageCount == 0 {
+				break
+			}
+		}
+	}
+
+	probabilities := make(map[Language]float64)
+	for _, language := range filteredLanguages {
+		sum := detector.penalizeUnseenNgrams(sums[language], len(ngramModel.ngrams)-matchedNgramCounts[language])
+		if sum < 0 {
+			probabilities[language] = sum
+		}
+	}
+	return probabilities
+}
+
+func (detector languageDetector) countUnigramsFromIndex(
+	entries map[string][]ngramIndexEntry,
+	unigramModel testDataLanguageModel,
+	filteredLanguages []Language,
+) map[Language]uint32 {
+	languageOccurrences := make([]uint32, Unknown+1)
+	for _, language := range filteredLanguages {
+		languageOccurrences[language]++
+	}
+
+	unigramCounts := make(map[Language]uint32)
+	for _, unigrams := range unigramModel.ngrams {
+		for _, entry := range entries[unigrams[0].value] {
+			if occurrences := languageOccurrences[entry.language]; occurrences > 0 {
+				unigramCounts[entry.language] += occurrences
+			}
+		}
+	}
+	return unigramCounts
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"sync"
+	"testing"
+)
+
+func newIndexedDetector(detector languageDetector) languageDetector {
+	detector.ngramIndex = &ngramIndex{}
+	detector.readiness = newReadiness()
+	detector.readiness.finish(nil)
+	detector.buildNgramIndex(allNgramLengths())
+	return detector
+}
+
+func TestBuildNgramIndex(t *testing.T) {
+	detector := newIndexedDetector(newDetectorForEnglishAndGerman())
+
+	entries := detector.availableNgramIndexEntries()
+	assert.NotNil(t, entries)
+	assert.ElementsMatch(
+		t,
+		[]ngramIndexEntry{{English, math.Log(0.29)}, {German, math.Log(0.3)}},
+		entries["alter"],
+	)
+	assert.ElementsMatch(
+		t,
+		[]ngramIndexEntry{{English, math.Log(0.04)}, {German, math.Log(0.09)}},
+		entries["e"],
+	)
+	assert.Nil(t, entries["w"])
+}
+
+func TestNgramIndexIsNotAvailableBeforeReadiness(t *testing.T) {
+	detector := newDetectorForEnglishAndGerman()
+	detector.ngramIndex = &ngramIndex{}
+	detector.readiness = newReadiness()
+	detector.buildNgramIndex(allNgramLengths())
+
+	assert.Nil(t, detector.availableNgramIndexEntries())
+	detector.readiness.finish(nil)
+	assert.NotNil(t, detector.availableNgramIndexEntries())
+	detector.ngramIndex.store(nil)
+	assert.Nil(t, detector.availableNgramIndexEntries())
+}
+
+func TestNgramIndexWithBackgroundPreloadingAndUnloading(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		WithInvertedNgramIndex().
+		WithBackgroundPreloading().
+		Build()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				language, _ := detector.DetectLanguageOf("Ich spreche Französisch nur ein bisschen.")
+				assert.Equal(t, German, language)
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		}()
+	}
+	assert.NoError(t, detector.WaitUntilReady(context.Background()))
+	detector.UnloadLanguageModels()
+	close(done)
+	wg.Wait()
+}
+
+func TestComputeLanguageProbabilitiesFromIndex(t *testing.T) {
+	detector := newIndexedDetector(newDetectorForEnglishAndGerman())
+	testCases := []testDataLanguageModel{
+		testDataModel([][]string{{"a"}, {"l"}, {"t"}, {"e"}, {"r"}}),
+		testDataModel([][]string{{"alt", "al", "a"}, {"lte", "lt", "l"}, {"ter", "te", "t"}, {"wxy", "wx", "w"}}),
+		testDataModel([][]string{{"alte", "alt", "al", "a"}, {"lter", "lte", "lt", "l"}, {"wxyz", "wxy", "wx", "w"}}),
+		testDataModel([][]string{{"aquas", "aqua", "aqu", "aq", "a"}}),
+	}
+	languages := []Language{English, German}
+	for _, ngramModel := range testCases {
+		expectedProbabilities := detectorForEnglishAndGerman.computeLanguageProbabilities(ngramModel, languages)
+		probabilities := detector.computeLanguageProbabilities(ngramModel, languages)
+		assert.Equal(t, len(expectedProbabilities), len(probabilities))
+
+		for language, expectedProbability := range expectedProbabilities {
+			message := fmt.Sprintf("unexpected probability for language %v and ngrams %v", language, ngramModel.ngrams)
+			assert.InDelta(t, expectedProbability, probabilities[language], delta, message)
+		}
+	}
+}
+
+func TestLanguageDetectionWithNgramIndex(t *testing.T) {
+	languages := []Language{English, French, German, Spanish, Italian, Portuguese}
+	detector := newLanguageDetector(languages, 0.0, true, false)
+	indexedDetector := newIndexedDetector(newLanguageDetector(languages, 0.0, true, false))
+	texts := []string{
+		"languages are awesome",
+		"Alter",
+		"Ich spreche Französisch nur ein bisschen.",
+		"A little bit is better than nothing.",
+		"Parlez-vous français?",
+		"la casa es muy bonita",
+	}
+	for _, text := range texts {
+		expectedConfidenceValues := detector.ComputeLanguageConfidenceValues(text)
+		confidenceValues := indexedDetector.ComputeLanguageConfidenceValues(text)
+		assert.Equal(t, len(expectedConfidenceValues), len(confidenceValues))
+
+		for i, expectedConfidence := range expectedConfidenceValues {
+			message := fmt.Sprintf("unexpected confidence values for text '%s'", text)
+			assert.Equal(t, expectedConfidence.Language(), confidenceValues[i].Language(), message)
+			assert.InDelta(t, expectedConfidence.Value(), confidenceValues[i].Value(), 0.000000001, message)
+		}
+	}
+}
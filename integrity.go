@@ -0,0 +1,289 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/pemistahl/lingua-go/serialization"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const checksumsFileName = "checksums.txt"
+
+var embeddedChecksums sync.Map
+
+// LanguageModelError is the error returned if a language model file
+// cannot be loaded or does not pass the integrity verification.
+type LanguageModelError struct {
+	// Path is the path of the affected language model file.
+	Path string
+	// Err is the underlying cause of the error.
+	Err error
+}
+
+func (err *LanguageModelError) Error() string {
+	return fmt.Sprintf("language model '%s' is invalid: %v", err.Path, err.Err)
+}
+
+func (err *LanguageModelError) Unwrap() error {
+	return err.Err
+}
+
+// LanguageModelErrors is a list of LanguageModelError which is returned
+// if more than one language model file is affected.
+type LanguageModelErrors []*LanguageModelError
+
+func (errs LanguageModelErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// VerifyLanguageModels verifies the integrity of all language models
+// embedded in the library.
+//
+// The checksum of each language model file is compared to the one recorded
+// in the checksums file next to it, and the file is decoded in order to make
+// sure that the language model can actually be loaded.
+//
+// A LanguageModelErrors value listing every invalid language model file
+// is returned if at least one of them does not pass the verification.
+// Otherwise, nil is returned.
+func VerifyLanguageModels() error {
+	var errs LanguageModelErrors
+	for _, language := range AllLanguages() {
+		if !language.hasLanguageModels() {
+			continue
+		}
+		directory := languageModelDirectory(language)
+		errs = append(errs, verifyLanguageModelDirectory(languageModels, directory)...)
+		for _, variantDirectory := range language.scriptVariantModelDirectories() {
+			errs = append(errs, verifyLanguageModelDirectory(languageModels, variantDirectory)...)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// VerifyLanguageModelFiles verifies the integrity of the language model
+// files stored in the given directory, such as those written by
+// CreateAndWriteLanguageModelFiles.
+//
+// The directory must contain a checksums file named checksums.txt which lists
+// the SHA-256 checksum of each language model file in the same format as the
+// sha256sum command line tool.
+//
+// A LanguageModelErrors value listing every invalid language model file
+// is returned if at least one of them does not pass the verification.
+// Otherwise, nil is returned.
+func VerifyLanguageModelFiles(directoryPath string) error {
+	errs := verifyLanguageModelDirectory(os.DirFS(directoryPath), ".")
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func verifyLanguageModelDirectory(fsys fs.FS, directory string) LanguageModelErrors {
+	checksums, err := readChecksums(fsys, directory)
+	if err != nil {
+		return LanguageModelErrors{{path.Join(directory, checksumsFileName), err}}
+	}
+
+	var errs LanguageModelErrors
+
+	entries, err := fs.ReadDir(fsys, directory)
+	if err != nil {
+		return LanguageModelErrors{{directory, err}}
+	}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if _, exists := checksums[fileName]; !exists && fileName != checksumsFileName {
+			errs = append(errs, &LanguageModelError{
+				path.Join(directory, fileName),
+				fmt.Errorf("no checksum recorded in %s", checksumsFileName),
+			})
+		}
+	}
+
+	fileNames := make([]string, 0, len(checksums))
+	for fileName := range checksums {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	for _, fileName := range fileNames {
+		_, err = loadVerifiedLanguageModel(fsys, directory, fileName, checksums[fileName])
+		if err != nil {
+			errs = append(errs, err.(*LanguageModelError))
+		}
+	}
+
+	return errs
+}
+
+func loadVerifiedLanguageModel(
+	fsys fs.FS,
+	directory string,
+	fileName string,
+	expectedChecksum string,
+) (*serialization.SerializableLanguageModel, error) {
+	filePath := path.Join(directory, fileName)
+	zipFileBytes, err := fs.ReadFile(fsys, filePath)
+	if err != nil {
+		return nil, &LanguageModelError{filePath, err}
+	}
+
+	checksum := sha256.Sum256(zipFileBytes)
+	if actualChecksum := hex.EncodeToString(checksum[:]); actualChecksum != expectedChecksum {
+		return nil, &LanguageModelError{
+			filePath,
+			fmt.Errorf("checksum %s does not match recorded checksum %s", actualChecksum, expectedChecksum),
+		}
+	}
+
+	model, err := decodeLanguageModel(zipFileBytes)
+	if err != nil {
+		return nil, &LanguageModelError{filePath, err}
+	}
+
+	if expectedNgramLength := ngramLengthOfFileName(fileName); expectedNgramLength > 0 &&
+		int(model.NgramLength) != expectedNgramLength {
+		return nil, &LanguageModelError{
+			filePath,
+			fmt.Errorf("ngram length %v does not match expected ngram length %v", model.NgramLength, expectedNgramLength),
+		}
+	}
+
+	return model, nil
+}
+
+func decodeLanguageModel(zipFileBytes []byte) (*serialization.SerializableLanguageModel, error) {
+	zipFile, err := zip.NewReader(bytes.NewReader(zipFileBytes), int64(len(zipFileBytes)))
+	if err != nil {
+		return nil, err
+	}
+	if len(zipFile.File) != 1 {
+		return nil, fmt.Errorf("zip file contains %v files instead of 1", len(zipFile.File))
+	}
+	protobufFile, err := zipFile.File[0].Open()
+	if err != nil {
+		return nil, err
+	}
+	defer protobufFile.Close()
+
+	protobufFileContent, err := io.ReadAll(protobufFile)
+	if err != nil {
+		return nil, err
+	}
+
+	model := serialization.SerializableLanguageModel{}
+	if err = proto.Unmarshal(protobufFileContent, &model); err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+func readChecksums(fsys fs.FS, directory string) (map[string]string, error) {
+	file, err := fsys.Open(path.Join(directory, checksumsFileName))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	checksums := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed line '%s'", line)
+		}
+		checksums[fields[1]] = fields[0]
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return checksums, nil
+}
+
+func loadEmbeddedChecksums(directory string) (map[string]string, error) {
+	existingChecksums, exists := embeddedChecksums.Load(directory)
+	if exists {
+		return existingChecksums.(map[string]string), nil
+	}
+	checksums, err := readChecksums(languageModels, directory)
+	if err != nil {
+		return nil, err
+	}
+	embeddedChecksums.Store(directory, checksums)
+	return checksums, nil
+}
+
+func writeChecksums(outputDirectoryPath string, fileNames []string) error {
+	var lines []string
+	for _, fileName := range fileNames {
+		fileContent, err := os.ReadFile(filepath.Join(outputDirectoryPath, fileName))
+		if err != nil {
+			return err
+		}
+		checksum := sha256.Sum256(fileContent)
+		lines = append(lines, fmt.Sprintf("%s  %s\n", hex.EncodeToString(checksum[:]), fileName))
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i][sha256.Size*2:] < lines[j][sha256.Size*2:]
+	})
+	return os.WriteFile(filepath.Join(outputDirectoryPath, checksumsFileName), []byte(strings.Join(lines, "")), 0644)
+}
+
+func languageModelDirectory(language Language) string {
+	isoCode := strings.ToLower(language.IsoCode639_1().String())
+	return fmt.Sprintf("language-models/%s", isoCode)
+}
+
+func languageModelFileName(ngramLength int) string {
+	return fmt.Sprintf("%ss.pb.bin.zip", getNgramNameByLength(ngramLength))
+}
+
+func ngramLengthOfFileName(fileName string) int {
+	for _, ngramLength := range allNgramLengths() {
+		if fileName == languageModelFileName(ngramLength) {
+			return ngramLength
+		}
+	}
+	return 0
+}
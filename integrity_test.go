@@ -0,0 +1,125 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyLanguageModels(t *testing.T) {
+	assert.NoError(t, VerifyLanguageModels())
+}
+
+func TestVerifyLanguageModelFiles(t *testing.T) {
+	testCases := []struct {
+		name          string
+		modify        func(outputDirectoryPath string)
+		expectedPaths []string
+	}{
+		{
+			"valid language models",
+			func(outputDirectoryPath string) {},
+			nil,
+		},
+		{
+			"truncated language model",
+			func(outputDirectoryPath string) {
+				filePath := filepath.Join(outputDirectoryPath, "trigrams.pb.bin.zip")
+				fileContent, _ := os.ReadFile(filePath)
+				_ = os.WriteFile(filePath, fileContent[:len(fileContent)/2], 0644)
+			},
+			[]string{"trigrams.pb.bin.zip"},
+		},
+		{
+			"missing language model",
+			func(outputDirectoryPath string) {
+				_ = os.Remove(filepath.Join(outputDirectoryPath, "bigrams.pb.bin.zip"))
+			},
+			[]string{"bigrams.pb.bin.zip"},
+		},
+		{
+			"unlisted language model",
+			func(outputDirectoryPath string) {
+				_ = os.WriteFile(filepath.Join(outputDirectoryPath, "sixgrams.pb.bin.zip"), []byte{}, 0644)
+			},
+			[]string{"sixgrams.pb.bin.zip"},
+		},
+		{
+			"missing checksums file",
+			func(outputDirectoryPath string) {
+				_ = os.Remove(filepath.Join(outputDirectoryPath, checksumsFileName))
+			},
+			[]string{checksumsFileName},
+		},
+	}
+	for _, testCase := range testCases {
+		inputFilePath := createTempInputFile(content)
+		outputDirectoryPath, _ := os.MkdirTemp("", "linguaOutputDirectory")
+
+		err := CreateAndWriteLanguageModelFiles(inputFilePath, outputDirectoryPath, English, "\\p{L}")
+		assert.NoError(t, err, "language model files could not be created")
+
+		testCase.modify(outputDirectoryPath)
+		err = VerifyLanguageModelFiles(outputDirectoryPath)
+
+		if testCase.expectedPaths == nil {
+			assert.NoError(t, err, testCase.name)
+		} else {
+			var errs LanguageModelErrors
+			assert.True(t, errors.As(err, &errs), testCase.name)
+			var paths []string
+			for _, modelErr := range errs {
+				paths = append(paths, modelErr.Path)
+			}
+			assert.Equal(t, testCase.expectedPaths, paths, testCase.name)
+		}
+
+		cleanUp(inputFilePath, outputDirectoryPath)
+	}
+}
+
+func TestLoadVerifiedLanguageModelDetectsChecksumMismatch(t *testing.T) {
+	_, err := loadVerifiedLanguageModel(languageModels, "language-models/en", "trigrams.pb.bin.zip", "0000")
+
+	var modelErr *LanguageModelError
+	assert.True(t, errors.As(err, &modelErr))
+	assert.Equal(t, "language-models/en/trigrams.pb.bin.zip", modelErr.Path)
+}
+
+func TestLoadSerializableLanguageModelOfMissingFile(t *testing.T) {
+	model, err := loadSerializableLanguageModel(Chinese, 3, false)
+	assert.Nil(t, model)
+	assert.NoError(t, err)
+}
+
+func TestBackgroundPreloadingReportsLanguageModelErrors(t *testing.T) {
+	languageModelErr := &LanguageModelError{"language-models/en/trigrams.pb.bin.zip", errors.New("corrupt")}
+
+	detector := newLanguageDetector([]Language{English, German}, 0.0, false, false)
+	detector.readiness = newReadiness()
+	detector.readiness.finish(LanguageModelErrors{languageModelErr})
+
+	assert.False(t, detector.IsReady())
+
+	err := detector.WaitUntilReady(context.Background())
+	assert.Equal(t, LanguageModelErrors{languageModelErr}, err)
+}
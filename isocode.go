@@ -34,6 +34,9 @@ const (
 	// AF is the ISO 639-1 code for Afrikaans.
 	AF IsoCode639_1 = iota
 
+	// AM is the ISO 639-1 code for Amharic.
+	AM
+
 	// AR is the ISO 639-1 code for Arabic.
 	AR
 
@@ -49,6 +52,9 @@ const (
 	// BN is the ISO 639-1 code for Bengali.
 	BN
 
+	// BO is the ISO 639-1 code for Tibetan.
+	BO
+
 	// BS is the ISO 639-1 code for Bosnian.
 	BS
 
@@ -67,6 +73,9 @@ const (
 	// DE is the ISO 639-1 code for German.
 	DE
 
+	// DV is the ISO 639-1 code for Dhivehi.
+	DV
+
 	// EL is the ISO 639-1 code for Greek.
 	EL
 
@@ -133,6 +142,12 @@ const (
 	// KK is the ISO 639-1 code for Kazakh.
 	KK
 
+	// KM is the ISO 639-1 code for Khmer.
+	KM
+
+	// KN is the ISO 639-1 code for Kannada.
+	KN
+
 	// KO is the ISO 639-1 code for Korean.
 	KO
 
@@ -142,6 +157,9 @@ const (
 	// LG is the ISO 639-1 code for Ganda.
 	LG
 
+	// LO is the ISO 639-1 code for Lao.
+	LO
+
 	// LT is the ISO 639-1 code for Lithuanian.
 	LT
 
@@ -154,6 +172,9 @@ const (
 	// MK is the ISO 639-1 code for Macedonian.
 	MK
 
+	// ML is the ISO 639-1 code for Malayalam.
+	ML
+
 	// MN is the ISO 639-1 code for Mongolian.
 	MN
 
@@ -163,6 +184,9 @@ const (
 	// MS is the ISO 639-1 code for Malay.
 	MS
 
+	// MY is the ISO 639-1 code for Burmese.
+	MY
+
 	// NB is the ISO 639-1 code for Bokmal.
 	NB
 
@@ -172,6 +196,9 @@ const (
 	// NN is the ISO 639-1 code for Nynorsk.
 	NN
 
+	// OR is the ISO 639-1 code for Odia.
+	OR
+
 	// PA is the ISO 639-1 code for Punjabi.
 	PA
 
@@ -187,6 +214,9 @@ const (
 	// RU is the ISO 639-1 code for Russian.
 	RU
 
+	// SI is the ISO 639-1 code for Sinhala.
+	SI
+
 	// SK is the ISO 639-1 code for Slovak.
 	SK
 
@@ -264,6 +294,9 @@ const (
 	// AFR is the ISO 639-3 code for Afrikaans.
 	AFR IsoCode639_3 = iota
 
+	// AMH is the ISO 639-3 code for Amharic.
+	AMH
+
 	// ARA is the ISO 639-3 code for Arabic.
 	ARA
 
@@ -276,6 +309,9 @@ const (
 	// BEN is the ISO 639-3 code for Bengali.
 	BEN
 
+	// BOD is the ISO 639-3 code for Tibetan.
+	BOD
+
 	// BOS is the ISO 639-3 code for Bosnian.
 	BOS
 
@@ -297,6 +333,9 @@ const (
 	// DEU is the ISO 639-3 code for German.
 	DEU
 
+	// DIV is the ISO 639-3 code for Dhivehi.
+	DIV
+
 	// ELL is the ISO 639-3 code for Greek.
 	ELL
 
@@ -354,15 +393,24 @@ const (
 	// JPN is the ISO 639-3 code for Japanese.
 	JPN
 
+	// KAN is the ISO 639-3 code for Kannada.
+	KAN
+
 	// KAT is the ISO 639-3 code for Georgian.
 	KAT
 
 	// KAZ is the ISO 639-3 code for Kazakh.
 	KAZ
 
+	// KHM is the ISO 639-3 code for Khmer.
+	KHM
+
 	// KOR is the ISO 639-3 code for Korean.
 	KOR
 
+	// LAO is the ISO 639-3 code for Lao.
+	LAO
+
 	// LAT is the ISO 639-3 code for Latin.
 	LAT
 
@@ -375,6 +423,9 @@ const (
 	// LUG is the ISO 639-3 code for Ganda.
 	LUG
 
+	// MAL is the ISO 639-3 code for Malayalam.
+	MAL
+
 	// MAR is the ISO 639-3 code for Marathi.
 	MAR
 
@@ -390,6 +441,9 @@ const (
 	// MSA is the ISO 639-3 code for Malay.
 	MSA
 
+	// MYA is the ISO 639-3 code for Burmese.
+	MYA
+
 	// NLD is the ISO 639-3 code for Dutch.
 	NLD
 
@@ -399,6 +453,9 @@ const (
 	// NOB is the ISO 639-3 code for Bokmal.
 	NOB
 
+	// ORI is the ISO 639-3 code for Odia.
+	ORI
+
 	// PAN is the ISO 639-3 code for Punjabi.
 	PAN
 
@@ -414,6 +471,9 @@ const (
 	// RUS is the ISO 639-3 code for Russian.
 	RUS
 
+	// SIN is the ISO 639-3 code for Sinhala.
+	SIN
+
 	// SLK is the ISO 639-3 code for Slovak.
 	SLK
 
@@ -9,86 +9,96 @@ func _() {
 	// Re-run the stringer command to generate them again.
 	var x [1]struct{}
 	_ = x[AF-0]
-	_ = x[AR-1]
-	_ = x[AZ-2]
-	_ = x[BE-3]
-	_ = x[BG-4]
-	_ = x[BN-5]
-	_ = x[BS-6]
-	_ = x[CA-7]
-	_ = x[CS-8]
-	_ = x[CY-9]
-	_ = x[DA-10]
-	_ = x[DE-11]
-	_ = x[EL-12]
-	_ = x[EN-13]
-	_ = x[EO-14]
-	_ = x[ES-15]
-	_ = x[ET-16]
-	_ = x[EU-17]
-	_ = x[FA-18]
-	_ = x[FI-19]
-	_ = x[FR-20]
-	_ = x[GA-21]
-	_ = x[GU-22]
-	_ = x[HE-23]
-	_ = x[HI-24]
-	_ = x[HR-25]
-	_ = x[HU-26]
-	_ = x[HY-27]
-	_ = x[ID-28]
-	_ = x[IS-29]
-	_ = x[IT-30]
-	_ = x[JA-31]
-	_ = x[KA-32]
-	_ = x[KK-33]
-	_ = x[KO-34]
-	_ = x[LA-35]
-	_ = x[LG-36]
-	_ = x[LT-37]
-	_ = x[LV-38]
-	_ = x[MI-39]
-	_ = x[MK-40]
-	_ = x[MN-41]
-	_ = x[MR-42]
-	_ = x[MS-43]
-	_ = x[NB-44]
-	_ = x[NL-45]
-	_ = x[NN-46]
-	_ = x[PA-47]
-	_ = x[PL-48]
-	_ = x[PT-49]
-	_ = x[RO-50]
-	_ = x[RU-51]
-	_ = x[SK-52]
-	_ = x[SL-53]
-	_ = x[SN-54]
-	_ = x[SO-55]
-	_ = x[SQ-56]
-	_ = x[SR-57]
-	_ = x[ST-58]
-	_ = x[SV-59]
-	_ = x[SW-60]
-	_ = x[TA-61]
-	_ = x[TE-62]
-	_ = x[TH-63]
-	_ = x[TL-64]
-	_ = x[TN-65]
-	_ = x[TR-66]
-	_ = x[TS-67]
-	_ = x[UK-68]
-	_ = x[UR-69]
-	_ = x[VI-70]
-	_ = x[XH-71]
-	_ = x[YO-72]
-	_ = x[ZH-73]
-	_ = x[ZU-74]
-	_ = x[UnknownIsoCode639_1-75]
+	_ = x[AM-1]
+	_ = x[AR-2]
+	_ = x[AZ-3]
+	_ = x[BE-4]
+	_ = x[BG-5]
+	_ = x[BN-6]
+	_ = x[BO-7]
+	_ = x[BS-8]
+	_ = x[CA-9]
+	_ = x[CS-10]
+	_ = x[CY-11]
+	_ = x[DA-12]
+	_ = x[DE-13]
+	_ = x[DV-14]
+	_ = x[EL-15]
+	_ = x[EN-16]
+	_ = x[EO-17]
+	_ = x[ES-18]
+	_ = x[ET-19]
+	_ = x[EU-20]
+	_ = x[FA-21]
+	_ = x[FI-22]
+	_ = x[FR-23]
+	_ = x[GA-24]
+	_ = x[GU-25]
+	_ = x[HE-26]
+	_ = x[HI-27]
+	_ = x[HR-28]
+	_ = x[HU-29]
+	_ = x[HY-30]
+	_ = x[ID-31]
+	_ = x[IS-32]
+	_ = x[IT-33]
+	_ = x[JA-34]
+	_ = x[KA-35]
+	_ = x[KK-36]
+	_ = x[KM-37]
+	_ = x[KN-38]
+	_ = x[KO-39]
+	_ = x[LA-40]
+	_ = x[LG-41]
+	_ = x[LO-42]
+	_ = x[LT-43]
+	_ = x[LV-44]
+	_ = x[MI-45]
+	_ = x[MK-46]
+	_ = x[ML-47]
+	_ = x[MN-48]
+	_ = x[MR-49]
+	_ = x[MS-50]
+	_ = x[MY-51]
+	_ = x[NB-52]
+	_ = x[NL-53]
+	_ = x[NN-54]
+	_ = x[OR-55]
+	_ = x[PA-56]
+	_ = x[PL-57]
+	_ = x[PT-58]
+	_ = x[RO-59]
+	_ = x[RU-60]
+	_ = x[SI-61]
+	_ = x[SK-62]
+	_ = x[SL-63]
+	_ = x[SN-64]
+	_ = x[SO-65]
+	_ = x[SQ-66]
+	_ = x[SR-67]
+	_ = x[ST-68]
+	_ = x[SV-69]
+	_ = x[SW-70]
+	_ = x[TA-71]
+	_ = x[TE-72]
+	_ = x[TH-73]
+	_ = x[TL-74]
+	_ = x[TN-75]
+	_ = x[TR-76]
+	_ = x[TS-77]
+	_ = x[UK-78]
+	_ = x[UR-79]
+	_ = x[VI-80]
+	_ = x[XH-81]
+	_ = x[YO-82]
+	_ = x[ZH-83]
+	_ = x[ZU-84]
+	_ = x[UnknownIsoCode639_1-85]
 }
 
-const _IsoCode639_1_name = "AFARAZBEBGBNBSCACSCYDADEELENEOESETEUFAFIFRGAGUHEHIHRHUHYIDISITJAKAKKKOLALGLTLVMIMKMNMRMSNBNLNNPAPLPTRORUSKSLSNSOSQSRSTSVSWTATETHTLTNTRTSUKURVIXHYOZHZUUnknownIsoCode639_1"
+const _IsoCode639_1_name = "AFAMARAZBEBGBNBOBSCACSCYDADEDVELENEOESETEUFAFIFRGAGUHEHIHRHUHYIDISITJAKAKKKMKNKOLALGLOLTLVMIMKMLMNMRMSMYNBNLNNORPAPLPTRORUSISKSLSNSOSQSRSTSVSWTATETHTLTNTRTSUKURVIXHYOZHZUUnknownIsoCode639_1"
 
-var _IsoCode639_1_index = [...]uint8{0, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34, 36, 38, 40, 42, 44, 46, 48, 50, 52, 54, 56, 58, 60, 62, 64, 66, 68, 70, 72, 74, 76, 78, 80, 82, 84, 86, 88, 90, 92, 94, 96, 98, 100, 102, 104, 106, 108, 110, 112, 114, 116, 118, 120, 122, 124, 126, 128, 130, 132, 134, 136, 138, 140, 142, 144, 146, 148, 150, 169}
+var _IsoCode639_1_index = [...]uint8{0, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34, 36, 38, 40, 42, 44, 46, 48, 50, 52, 54, 56, 58, 60, 62, 64, 66, 68, 70, 72, 74, 76, 78, 80, 82, 84, 86, 88, 90, 92, 94, 96, 98, 100, 102, 104, 106, 108, 110, 112, 114, 116, 118, 120, 122, 124, 126, 128, 130, 132, 134, 136, 138, 140, 142, 144, 146, 148, 150, 152, 154, 156, 158, 160, 162, 164, 166, 168, 170, 189}
 
 func (i IsoCode639_1) String() string {
 	if i < 0 || i >= IsoCode639_1(len(_IsoCode639_1_index)-1) {
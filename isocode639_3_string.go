@@ -9,86 +9,96 @@ func _() {
 	// Re-run the stringer command to generate them again.
 	var x [1]struct{}
 	_ = x[AFR-0]
-	_ = x[ARA-1]
-	_ = x[AZE-2]
-	_ = x[BEL-3]
-	_ = x[BEN-4]
-	_ = x[BOS-5]
-	_ = x[BUL-6]
-	_ = x[CAT-7]
-	_ = x[CES-8]
-	_ = x[CYM-9]
-	_ = x[DAN-10]
-	_ = x[DEU-11]
-	_ = x[ELL-12]
-	_ = x[ENG-13]
-	_ = x[EPO-14]
-	_ = x[EST-15]
-	_ = x[EUS-16]
-	_ = x[FAS-17]
-	_ = x[FIN-18]
-	_ = x[FRA-19]
-	_ = x[GLE-20]
-	_ = x[GUJ-21]
-	_ = x[HEB-22]
-	_ = x[HIN-23]
-	_ = x[HRV-24]
-	_ = x[HUN-25]
-	_ = x[HYE-26]
-	_ = x[IND-27]
-	_ = x[ISL-28]
-	_ = x[ITA-29]
-	_ = x[JPN-30]
-	_ = x[KAT-31]
-	_ = x[KAZ-32]
-	_ = x[KOR-33]
-	_ = x[LAT-34]
-	_ = x[LAV-35]
-	_ = x[LIT-36]
-	_ = x[LUG-37]
-	_ = x[MAR-38]
-	_ = x[MKD-39]
-	_ = x[MON-40]
-	_ = x[MRI-41]
-	_ = x[MSA-42]
-	_ = x[NLD-43]
-	_ = x[NNO-44]
-	_ = x[NOB-45]
-	_ = x[PAN-46]
-	_ = x[POL-47]
-	_ = x[POR-48]
-	_ = x[RON-49]
-	_ = x[RUS-50]
-	_ = x[SLK-51]
-	_ = x[SLV-52]
-	_ = x[SNA-53]
-	_ = x[SOM-54]
-	_ = x[SOT-55]
-	_ = x[SPA-56]
-	_ = x[SQI-57]
-	_ = x[SRP-58]
-	_ = x[SWA-59]
-	_ = x[SWE-60]
-	_ = x[TAM-61]
-	_ = x[TEL-62]
-	_ = x[TGL-63]
-	_ = x[THA-64]
-	_ = x[TSN-65]
-	_ = x[TSO-66]
-	_ = x[TUR-67]
-	_ = x[UKR-68]
-	_ = x[URD-69]
-	_ = x[VIE-70]
-	_ = x[XHO-71]
-	_ = x[YOR-72]
-	_ = x[ZHO-73]
-	_ = x[ZUL-74]
-	_ = x[UnknownIsoCode639_3-75]
+	_ = x[AMH-1]
+	_ = x[ARA-2]
+	_ = x[AZE-3]
+	_ = x[BEL-4]
+	_ = x[BEN-5]
+	_ = x[BOD-6]
+	_ = x[BOS-7]
+	_ = x[BUL-8]
+	_ = x[CAT-9]
+	_ = x[CES-10]
+	_ = x[CYM-11]
+	_ = x[DAN-12]
+	_ = x[DEU-13]
+	_ = x[DIV-14]
+	_ = x[ELL-15]
+	_ = x[ENG-16]
+	_ = x[EPO-17]
+	_ = x[EST-18]
+	_ = x[EUS-19]
+	_ = x[FAS-20]
+	_ = x[FIN-21]
+	_ = x[FRA-22]
+	_ = x[GLE-23]
+	_ = x[GUJ-24]
+	_ = x[HEB-25]
+	_ = x[HIN-26]
+	_ = x[HRV-27]
+	_ = x[HUN-28]
+	_ = x[HYE-29]
+	_ = x[IND-30]
+	_ = x[ISL-31]
+	_ = x[ITA-32]
+	_ = x[JPN-33]
+	_ = x[KAN-34]
+	_ = x[KAT-35]
+	_ = x[KAZ-36]
+	_ = x[KHM-37]
+	_ = x[KOR-38]
+	_ = x[LAO-39]
+	_ = x[LAT-40]
+	_ = x[LAV-41]
+	_ = x[LIT-42]
+	_ = x[LUG-43]
+	_ = x[MAL-44]
+	_ = x[MAR-45]
+	_ = x[MKD-46]
+	_ = x[MON-47]
+	_ = x[MRI-48]
+	_ = x[MSA-49]
+	_ = x[MYA-50]
+	_ = x[NLD-51]
+	_ = x[NNO-52]
+	_ = x[NOB-53]
+	_ = x[ORI-54]
+	_ = x[PAN-55]
+	_ = x[POL-56]
+	_ = x[POR-57]
+	_ = x[RON-58]
+	_ = x[RUS-59]
+	_ = x[SIN-60]
+	_ = x[SLK-61]
+	_ = x[SLV-62]
+	_ = x[SNA-63]
+	_ = x[SOM-64]
+	_ = x[SOT-65]
+	_ = x[SPA-66]
+	_ = x[SQI-67]
+	_ = x[SRP-68]
+	_ = x[SWA-69]
+	_ = x[SWE-70]
+	_ = x[TAM-71]
+	_ = x[TEL-72]
+	_ = x[TGL-73]
+	_ = x[THA-74]
+	_ = x[TSN-75]
+	_ = x[TSO-76]
+	_ = x[TUR-77]
+	_ = x[UKR-78]
+	_ = x[URD-79]
+	_ = x[VIE-80]
+	_ = x[XHO-81]
+	_ = x[YOR-82]
+	_ = x[ZHO-83]
+	_ = x[ZUL-84]
+	_ = x[UnknownIsoCode639_3-85]
 }
 
-const _IsoCode639_3_name = "AFRARAAZEBELBENBOSBULCATCESCYMDANDEUELLENGEPOESTEUSFASFINFRAGLEGUJHEBHINHRVHUNHYEINDISLITAJPNKATKAZKORLATLAVLITLUGMARMKDMONMRIMSANLDNNONOBPANPOLPORRONRUSSLKSLVSNASOMSOTSPASQISRPSWASWETAMTELTGLTHATSNTSOTURUKRURDVIEXHOYORZHOZULUnknownIsoCode639_3"
+const _IsoCode639_3_name = "AFRAMHARAAZEBELBENBODBOSBULCATCESCYMDANDEUDIVELLENGEPOESTEUSFASFINFRAGLEGUJHEBHINHRVHUNHYEINDISLITAJPNKANKATKAZKHMKORLAOLATLAVLITLUGMALMARMKDMONMRIMSAMYANLDNNONOBORIPANPOLPORRONRUSSINSLKSLVSNASOMSOTSPASQISRPSWASWETAMTELTGLTHATSNTSOTURUKRURDVIEXHOYORZHOZULUnknownIsoCode639_3"
 
-var _IsoCode639_3_index = [...]uint8{0, 3, 6, 9, 12, 15, 18, 21, 24, 27, 30, 33, 36, 39, 42, 45, 48, 51, 54, 57, 60, 63, 66, 69, 72, 75, 78, 81, 84, 87, 90, 93, 96, 99, 102, 105, 108, 111, 114, 117, 120, 123, 126, 129, 132, 135, 138, 141, 144, 147, 150, 153, 156, 159, 162, 165, 168, 171, 174, 177, 180, 183, 186, 189, 192, 195, 198, 201, 204, 207, 210, 213, 216, 219, 222, 225, 244}
+var _IsoCode639_3_index = [...]uint16{0, 3, 6, 9, 12, 15, 18, 21, 24, 27, 30, 33, 36, 39, 42, 45, 48, 51, 54, 57, 60, 63, 66, 69, 72, 75, 78, 81, 84, 87, 90, 93, 96, 99, 102, 105, 108, 111, 114, 117, 120, 123, 126, 129, 132, 135, 138, 141, 144, 147, 150, 153, 156, 159, 162, 165, 168, 171, 174, 177, 180, 183, 186, 189, 192, 195, 198, 201, 204, 207, 210, 213, 216, 219, 222, 225, 228, 231, 234, 237, 240, 243, 246, 249, 252, 255, 274}
 
 func (i IsoCode639_3) String() string {
 	if i < 0 || i >= IsoCode639_3(len(_IsoCode639_3_index)-1) {
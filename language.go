@@ -16,7 +16,7 @@
 
 package lingua
 
-// Language is the type used for enumerating the so far 75 languages which can
+// Language is the type used for enumerating the so far 85 languages which can
 // be detected by Lingua.
 //
 //go:generate stringer -type=Language
@@ -25,6 +25,7 @@ type Language int
 const (
 	Afrikaans Language = iota
 	Albanian
+	Amharic
 	Arabic
 	Armenian
 	Azerbaijani
@@ -34,11 +35,13 @@ const (
 	Bokmal
 	Bosnian
 	Bulgarian
+	Burmese
 	Catalan
 	Chinese
 	Croatian
 	Czech
 	Danish
+	Dhivehi
 	Dutch
 	English
 	Esperanto
@@ -58,17 +61,22 @@ const (
 	Irish
 	Italian
 	Japanese
+	Kannada
 	Kazakh
+	Khmer
 	Korean
+	Lao
 	Latin
 	Latvian
 	Lithuanian
 	Macedonian
 	Malay
+	Malayalam
 	Maori
 	Marathi
 	Mongolian
 	Nynorsk
+	Odia
 	Persian
 	Polish
 	Portuguese
@@ -77,6 +85,7 @@ const (
 	Russian
 	Serbian
 	Shona
+	Sinhala
 	Slovak
 	Slovene
 	Somali
@@ -88,6 +97,7 @@ const (
 	Tamil
 	Telugu
 	Thai
+	Tibetan
 	Tsonga
 	Tswana
 	Turkish
@@ -187,6 +197,8 @@ func (language Language) IsoCode639_1() IsoCode639_1 {
 		return AF
 	case Albanian:
 		return SQ
+	case Amharic:
+		return AM
 	case Arabic:
 		return AR
 	case Armenian:
@@ -205,6 +217,8 @@ func (language Language) IsoCode639_1() IsoCode639_1 {
 		return BS
 	case Bulgarian:
 		return BG
+	case Burmese:
+		return MY
 	case Catalan:
 		return CA
 	case Chinese:
@@ -215,6 +229,8 @@ func (language Language) IsoCode639_1() IsoCode639_1 {
 		return CS
 	case Danish:
 		return DA
+	case Dhivehi:
+		return DV
 	case Dutch:
 		return NL
 	case English:
@@ -253,10 +269,16 @@ func (language Language) IsoCode639_1() IsoCode639_1 {
 		return IT
 	case Japanese:
 		return JA
+	case Kannada:
+		return KN
 	case Kazakh:
 		return KK
+	case Khmer:
+		return KM
 	case Korean:
 		return KO
+	case Lao:
+		return LO
 	case Latin:
 		return LA
 	case Latvian:
@@ -267,6 +289,8 @@ func (language Language) IsoCode639_1() IsoCode639_1 {
 		return MK
 	case Malay:
 		return MS
+	case Malayalam:
+		return ML
 	case Maori:
 		return MI
 	case Marathi:
@@ -275,6 +299,8 @@ func (language Language) IsoCode639_1() IsoCode639_1 {
 		return MN
 	case Nynorsk:
 		return NN
+	case Odia:
+		return OR
 	case Persian:
 		return FA
 	case Polish:
@@ -291,6 +317,8 @@ func (language Language) IsoCode639_1() IsoCode639_1 {
 		return SR
 	case Shona:
 		return SN
+	case Sinhala:
+		return SI
 	case Slovak:
 		return SK
 	case Slovene:
@@ -313,6 +341,8 @@ func (language Language) IsoCode639_1() IsoCode639_1 {
 		return TE
 	case Thai:
 		return TH
+	case Tibetan:
+		return BO
 	case Tsonga:
 		return TS
 	case Tswana:
@@ -347,6 +377,8 @@ func (language Language) IsoCode639_3() IsoCode639_3 {
 		return AFR
 	case Albanian:
 		return SQI
+	case Amharic:
+		return AMH
 	case Arabic:
 		return ARA
 	case Armenian:
@@ -365,6 +397,8 @@ func (language Language) IsoCode639_3() IsoCode639_3 {
 		return BOS
 	case Bulgarian:
 		return BUL
+	case Burmese:
+		return MYA
 	case Catalan:
 		return CAT
 	case Chinese:
@@ -375,6 +409,8 @@ func (language Language) IsoCode639_3() IsoCode639_3 {
 		return CES
 	case Danish:
 		return DAN
+	case Dhivehi:
+		return DIV
 	case Dutch:
 		return NLD
 	case English:
@@ -413,10 +449,16 @@ func (language Language) IsoCode639_3() IsoCode639_3 {
 		return ITA
 	case Japanese:
 		return JPN
+	case Kannada:
+		return KAN
 	case Kazakh:
 		return KAZ
+	case Khmer:
+		return KHM
 	case Korean:
 		return KOR
+	case Lao:
+		return LAO
 	case Latin:
 		return LAT
 	case Latvian:
@@ -427,6 +469,8 @@ func (language Language) IsoCode639_3() IsoCode639_3 {
 		return MKD
 	case Malay:
 		return MSA
+	case Malayalam:
+		return MAL
 	case Maori:
 		return MRI
 	case Marathi:
@@ -435,6 +479,8 @@ func (language Language) IsoCode639_3() IsoCode639_3 {
 		return MON
 	case Nynorsk:
 		return NNO
+	case Odia:
+		return ORI
 	case Persian:
 		return FAS
 	case Polish:
@@ -451,6 +497,8 @@ func (language Language) IsoCode639_3() IsoCode639_3 {
 		return SRP
 	case Shona:
 		return SNA
+	case Sinhala:
+		return SIN
 	case Slovak:
 		return SLK
 	case Slovene:
@@ -473,6 +521,8 @@ func (language Language) IsoCode639_3() IsoCode639_3 {
 		return TEL
 	case Thai:
 		return THA
+	case Tibetan:
+		return BOD
 	case Tsonga:
 		return TSO
 	case Tswana:
@@ -567,12 +617,18 @@ func (language Language) alphabets() []alphabet {
 		return []alphabet{arabic}
 	case Hindi, Marathi:
 		return []alphabet{devanagari}
+	case Amharic:
+		return []alphabet{ethiopic}
 	case Armenian:
 		return []alphabet{armenian}
 	case Bengali:
 		return []alphabet{bengali}
+	case Burmese:
+		return []alphabet{myanmar}
 	case Chinese:
 		return []alphabet{han}
+	case Dhivehi:
+		return []alphabet{thaana}
 	case Georgian:
 		return []alphabet{georgian}
 	case Greek:
@@ -583,21 +639,47 @@ func (language Language) alphabets() []alphabet {
 		return []alphabet{hebrew}
 	case Japanese:
 		return []alphabet{hiragana, katakana, han}
+	case Kannada:
+		return []alphabet{kannada}
+	case Khmer:
+		return []alphabet{khmer}
 	case Korean:
 		return []alphabet{hangul}
+	case Lao:
+		return []alphabet{lao}
+	case Malayalam:
+		return []alphabet{malayalam}
+	case Odia:
+		return []alphabet{oriya}
 	case Punjabi:
 		return []alphabet{gurmukhi}
+	case Sinhala:
+		return []alphabet{sinhala}
 	case Tamil:
 		return []alphabet{tamil}
 	case Telugu:
 		return []alphabet{telugu}
 	case Thai:
 		return []alphabet{thai}
+	case Tibetan:
+		return []alphabet{tibetan}
 	default:
 		return []alphabet{}
 	}
 }
 
+// hasLanguageModels reports whether language models exist for the language.
+// Languages written in a script which no other supported language uses are
+// identified by the rule engine alone, so they come without language models.
+func (language Language) hasLanguageModels() bool {
+	switch language {
+	case Amharic, Burmese, Dhivehi, Kannada, Khmer, Lao, Malayalam, Odia, Sinhala, Tibetan, Unknown:
+		return false
+	default:
+		return true
+	}
+}
+
 func (language Language) uniqueCharacters() string {
 	switch language {
 	case Azerbaijani:
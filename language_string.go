@@ -10,85 +10,95 @@ func _() {
 	var x [1]struct{}
 	_ = x[Afrikaans-0]
 	_ = x[Albanian-1]
-	_ = x[Arabic-2]
-	_ = x[Armenian-3]
-	_ = x[Azerbaijani-4]
-	_ = x[Basque-5]
-	_ = x[Belarusian-6]
-	_ = x[Bengali-7]
-	_ = x[Bokmal-8]
-	_ = x[Bosnian-9]
-	_ = x[Bulgarian-10]
-	_ = x[Catalan-11]
-	_ = x[Chinese-12]
-	_ = x[Croatian-13]
-	_ = x[Czech-14]
-	_ = x[Danish-15]
-	_ = x[Dutch-16]
-	_ = x[English-17]
-	_ = x[Esperanto-18]
-	_ = x[Estonian-19]
-	_ = x[Finnish-20]
-	_ = x[French-21]
-	_ = x[Ganda-22]
-	_ = x[Georgian-23]
-	_ = x[German-24]
-	_ = x[Greek-25]
-	_ = x[Gujarati-26]
-	_ = x[Hebrew-27]
-	_ = x[Hindi-28]
-	_ = x[Hungarian-29]
-	_ = x[Icelandic-30]
-	_ = x[Indonesian-31]
-	_ = x[Irish-32]
-	_ = x[Italian-33]
-	_ = x[Japanese-34]
-	_ = x[Kazakh-35]
-	_ = x[Korean-36]
-	_ = x[Latin-37]
-	_ = x[Latvian-38]
-	_ = x[Lithuanian-39]
-	_ = x[Macedonian-40]
-	_ = x[Malay-41]
-	_ = x[Maori-42]
-	_ = x[Marathi-43]
-	_ = x[Mongolian-44]
-	_ = x[Nynorsk-45]
-	_ = x[Persian-46]
-	_ = x[Polish-47]
-	_ = x[Portuguese-48]
-	_ = x[Punjabi-49]
-	_ = x[Romanian-50]
-	_ = x[Russian-51]
-	_ = x[Serbian-52]
-	_ = x[Shona-53]
-	_ = x[Slovak-54]
-	_ = x[Slovene-55]
-	_ = x[Somali-56]
-	_ = x[Sotho-57]
-	_ = x[Spanish-58]
-	_ = x[Swahili-59]
-	_ = x[Swedish-60]
-	_ = x[Tagalog-61]
-	_ = x[Tamil-62]
-	_ = x[Telugu-63]
-	_ = x[Thai-64]
-	_ = x[Tsonga-65]
-	_ = x[Tswana-66]
-	_ = x[Turkish-67]
-	_ = x[Ukrainian-68]
-	_ = x[Urdu-69]
-	_ = x[Vietnamese-70]
-	_ = x[Welsh-71]
-	_ = x[Xhosa-72]
-	_ = x[Yoruba-73]
-	_ = x[Zulu-74]
-	_ = x[Unknown-75]
+	_ = x[Amharic-2]
+	_ = x[Arabic-3]
+	_ = x[Armenian-4]
+	_ = x[Azerbaijani-5]
+	_ = x[Basque-6]
+	_ = x[Belarusian-7]
+	_ = x[Bengali-8]
+	_ = x[Bokmal-9]
+	_ = x[Bosnian-10]
+	_ = x[Bulgarian-11]
+	_ = x[Burmese-12]
+	_ = x[Catalan-13]
+	_ = x[Chinese-14]
+	_ = x[Croatian-15]
+	_ = x[Czech-16]
+	_ = x[Danish-17]
+	_ = x[Dhivehi-18]
+	_ = x[Dutch-19]
+	_ = x[English-20]
+	_ = x[Esperanto-21]
+	_ = x[Estonian-22]
+	_ = x[Finnish-23]
+	_ = x[French-24]
+	_ = x[Ganda-25]
+	_ = x[Georgian-26]
+	_ = x[German-27]
+	_ = x[Greek-28]
+	_ = x[Gujarati-29]
+	_ = x[Hebrew-30]
+	_ = x[Hindi-31]
+	_ = x[Hungarian-32]
+	_ = x[Icelandic-33]
+	_ = x[Indonesian-34]
+	_ = x[Irish-35]
+	_ = x[Italian-36]
+	_ = x[Japanese-37]
+	_ = x[Kannada-38]
+	_ = x[Kazakh-39]
+	_ = x[Khmer-40]
+	_ = x[Korean-41]
+	_ = x[Lao-42]
+	_ = x[Latin-43]
+	_ = x[Latvian-44]
+	_ = x[Lithuanian-45]
+	_ = x[Macedonian-46]
+	_ = x[Malay-47]
+	_ = x[Malayalam-48]
+	_ = x[Maori-49]
+	_ = x[Marathi-50]
+	_ = x[Mongolian-51]
+	_ = x[Nynorsk-52]
+	_ = x[Odia-53]
+	_ = x[Persian-54]
+	_ = x[Polish-55]
+	_ = x[Portuguese-56]
+	_ = x[Punjabi-57]
+	_ = x[Romanian-58]
+	_ = x[Russian-59]
+	_ = x[Serbian-60]
+	_ = x[Shona-61]
+	_ = x[Sinhala-62]
+	_ = x[Slovak-63]
+	_ = x[Slovene-64]
+	_ = x[Somali-65]
+	_ = x[Sotho-66]
+	_ = x[Spanish-67]
+	_ = x[Swahili-68]
+	_ = x[Swedish-69]
+	_ = x[Tagalog-70]
+	_ = x[Tamil-71]
+	_ = x[Telugu-72]
+	_ = x[Thai-73]
+	_ = x[Tibetan-74]
+	_ = x[Tsonga-75]
+	_ = x[Tswana-76]
+	_ = x[Turkish-77]
+	_ = x[Ukrainian-78]
+	_ = x[Urdu-79]
+	_ = x[Vietnamese-80]
+	_ = x[Welsh-81]
+	_ = x[Xhosa-82]
+	_ = x[Yoruba-83]
+	_ = x[Zulu-84]
+	_ = x[Unknown-85]
 }
 
-const _Language_name = "AfrikaansAlbanianArabicArmenianAzerbaijaniBasqueBelarusianBengaliBokmalBosnianBulgarianCatalanChineseCroatianCzechDanishDutchEnglishEsperantoEstonianFinnishFrenchGandaGeorgianGermanGreekGujaratiHebrewHindiHungarianIcelandicIndonesianIrishItalianJapaneseKazakhKoreanLatinLatvianLithuanianMacedonianMalayMaoriMarathiMongolianNynorskPersianPolishPortuguesePunjabiRomanianRussianSerbianShonaSlovakSloveneSomaliSothoSpanishSwahiliSwedishTagalogTamilTeluguThaiTsongaTswanaTurkishUkrainianUrduVietnameseWelshXhosaYorubaZuluUnknown"
+const _Language_name = "AfrikaansAlbanianAmharicArabicArmenianAzerbaijaniBasqueBelarusianBengaliBokmalBosnianBulgarianBurmeseCatalanChineseCroatianCzechDanishDhivehiDutchEnglishEsperantoEstonianFinnishFrenchGandaGeorgianGermanGreekGujaratiHebrewHindiHungarianIcelandicIndonesianIrishItalianJapaneseKannadaKazakhKhmerKoreanLaoLatinLatvianLithuanianMacedonianMalayMalayalamMaoriMarathiMongolianNynorskOdiaPersianPolishPortuguesePunjabiRomanianRussianSerbianShonaSinhalaSlovakSloveneSomaliSothoSpanishSwahiliSwedishTagalogTamilTeluguThaiTibetanTsongaTswanaTurkishUkrainianUrduVietnameseWelshXhosaYorubaZuluUnknown"
 
-var _Language_index = [...]uint16{0, 9, 17, 23, 31, 42, 48, 58, 65, 71, 78, 87, 94, 101, 109, 114, 120, 125, 132, 141, 149, 156, 162, 167, 175, 181, 186, 194, 200, 205, 214, 223, 233, 238, 245, 253, 259, 265, 270, 277, 287, 297, 302, 307, 314, 323, 330, 337, 343, 353, 360, 368, 375, 382, 387, 393, 400, 406, 411, 418, 425, 432, 439, 444, 450, 454, 460, 466, 473, 482, 486, 496, 501, 506, 512, 516, 523}
+var _Language_index = [...]uint16{0, 9, 17, 24, 30, 38, 49, 55, 65, 72, 78, 85, 94, 101, 108, 115, 123, 128, 134, 141, 146, 153, 162, 170, 177, 183, 188, 196, 202, 207, 215, 221, 226, 235, 244, 254, 259, 266, 274, 281, 287, 292, 298, 301, 306, 313, 323, 333, 338, 347, 352, 359, 368, 375, 379, 386, 392, 402, 409, 417, 424, 431, 436, 443, 449, 456, 462, 467, 474, 481, 488, 495, 500, 506, 510, 517, 523, 529, 536, 545, 549, 559, 564, 569, 575, 579, 586}
 
 func (language Language) String() string {
 	if language < 0 || language >= Language(len(_Language_index)-1) {
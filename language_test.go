@@ -27,6 +27,7 @@ func TestAllLanguages(t *testing.T) {
 		[]Language{
 			Afrikaans,
 			Albanian,
+			Amharic,
 			Arabic,
 			Armenian,
 			Azerbaijani,
@@ -36,11 +37,13 @@ func TestAllLanguages(t *testing.T) {
 			Bokmal,
 			Bosnian,
 			Bulgarian,
+			Burmese,
 			Catalan,
 			Chinese,
 			Croatian,
 			Czech,
 			Danish,
+			Dhivehi,
 			Dutch,
 			English,
 			Esperanto,
@@ -60,17 +63,22 @@ func TestAllLanguages(t *testing.T) {
 			Irish,
 			Italian,
 			Japanese,
+			Kannada,
 			Kazakh,
+			Khmer,
 			Korean,
+			Lao,
 			Latin,
 			Latvian,
 			Lithuanian,
 			Macedonian,
 			Malay,
+			Malayalam,
 			Maori,
 			Marathi,
 			Mongolian,
 			Nynorsk,
+			Odia,
 			Persian,
 			Polish,
 			Portuguese,
@@ -79,6 +87,7 @@ func TestAllLanguages(t *testing.T) {
 			Russian,
 			Serbian,
 			Shona,
+			Sinhala,
 			Slovak,
 			Slovene,
 			Somali,
@@ -90,6 +99,7 @@ func TestAllLanguages(t *testing.T) {
 			Tamil,
 			Telugu,
 			Thai,
+			Tibetan,
 			Tsonga,
 			Tswana,
 			Turkish,
@@ -110,6 +120,7 @@ func TestAllSpokenLanguages(t *testing.T) {
 		[]Language{
 			Afrikaans,
 			Albanian,
+			Amharic,
 			Arabic,
 			Armenian,
 			Azerbaijani,
@@ -119,11 +130,13 @@ func TestAllSpokenLanguages(t *testing.T) {
 			Bokmal,
 			Bosnian,
 			Bulgarian,
+			Burmese,
 			Catalan,
 			Chinese,
 			Croatian,
 			Czech,
 			Danish,
+			Dhivehi,
 			Dutch,
 			English,
 			Esperanto,
@@ -143,16 +156,21 @@ func TestAllSpokenLanguages(t *testing.T) {
 			Irish,
 			Italian,
 			Japanese,
+			Kannada,
 			Kazakh,
+			Khmer,
 			Korean,
+			Lao,
 			Latvian,
 			Lithuanian,
 			Macedonian,
 			Malay,
+			Malayalam,
 			Maori,
 			Marathi,
 			Mongolian,
 			Nynorsk,
+			Odia,
 			Persian,
 			Polish,
 			Portuguese,
@@ -161,6 +179,7 @@ func TestAllSpokenLanguages(t *testing.T) {
 			Russian,
 			Serbian,
 			Shona,
+			Sinhala,
 			Slovak,
 			Slovene,
 			Somali,
@@ -172,6 +191,7 @@ func TestAllSpokenLanguages(t *testing.T) {
 			Tamil,
 			Telugu,
 			Thai,
+			Tibetan,
 			Tsonga,
 			Tswana,
 			Turkish,
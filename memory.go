@@ -0,0 +1,210 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"sort"
+	"sync"
+)
+
+// Sizes in bytes and limits of the parts of Go's built-in map implementation,
+// a swiss table, which are used for estimating the memory usage of a language
+// model. A map consists of a directory of tables, each holding a power of two
+// of groups. A group consists of a control word and eight slots, which hold a
+// key and its value padded together.
+const (
+	mapGroupSlots       = 8
+	mapMaxAvgGroupLoad  = 7
+	mapMaxTableCapacity = 1024
+	mapHeaderBytes      = 48
+	mapTableBytes       = 32
+	mapControlWordBytes = 8
+	pointerBytes        = 8
+	stringHeaderBytes   = 16
+	memoryAlignment     = 8
+)
+
+// ModelMemoryStats is the interface describing the estimated memory usage
+// of a language model which is currently loaded into memory. It is computed
+// by LanguageModelMemoryUsage.
+type ModelMemoryStats interface {
+	// Language returns the language of the language model.
+	Language() Language
+
+	// NgramLength returns the ngram length of the language model.
+	NgramLength() int
+
+	// QuantizationBits returns the number of bits the probabilities of the
+	// language model have been quantized to, or 0 for full precision models.
+	QuantizationBits() int
+
+	// NgramCount returns the number of ngrams in the language model.
+	NgramCount() int
+
+	// EstimatedBytes returns the estimated number of bytes which the
+	// language model occupies in memory.
+	EstimatedBytes() uint64
+}
+
+type modelMemoryStats struct {
+	language         Language
+	ngramLength      int
+	quantizationBits int
+	ngramCount       int
+	estimatedBytes   uint64
+}
+
+func (stats modelMemoryStats) Language() Language {
+	return stats.language
+}
+
+func (stats modelMemoryStats) NgramLength() int {
+	return stats.ngramLength
+}
+
+func (stats modelMemoryStats) QuantizationBits() int {
+	return stats.quantizationBits
+}
+
+func (stats modelMemoryStats) NgramCount() int {
+	return stats.ngramCount
+}
+
+func (stats modelMemoryStats) EstimatedBytes() uint64 {
+	return stats.estimatedBytes
+}
+
+// LanguageModelMemoryUsage reports the estimated memory usage and the number
+// of ngrams of each language model which is currently loaded into memory.
+//
+// As language models are shared between all LanguageDetector instances, the
+// report covers the language models of all instances. The values are sorted
+// by language, ngram length and quantization bits. The estimation is based on
+// the layout of Go's built-in map, or of the arrays of quantized language
+// models, and does not include the memory used by the Go runtime itself, so it
+// can deviate from the actual memory usage.
+//
+// The inverted ngram index built by LanguageDetectorBuilder.WithInvertedNgramIndex
+// is not included. It belongs to a single LanguageDetector instance rather
+// than being shared, and it holds another copy of the probabilities of all
+// ngrams of the detector's languages in addition to the language models.
+func LanguageModelMemoryUsage() []ModelMemoryStats {
+	// A detector without languages refers to the language models
+	// which are shared between all instances.
+	detector := newLanguageDetector(nil, 0.0, false, false)
+	var stats []modelMemoryStats
+	for _, ngramLength := range allNgramLengths() {
+		stats = append(stats, collectModelMemoryStats(detector.languageModelsOfLength(ngramLength), ngramLength)...)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		first, second := stats[i], stats[j]
+		if first.language != second.language {
+			return first.language < second.language
+		}
+		if first.ngramLength != second.ngramLength {
+			return first.ngramLength < second.ngramLength
+		}
+		return first.quantizationBits < second.quantizationBits
+	})
+
+	result := make([]ModelMemoryStats, len(stats))
+	for i, s := range stats {
+		result[i] = s
+	}
+	return result
+}
+
+func collectModelMemoryStats(models *sync.Map, ngramLength int) []modelMemoryStats {
+	var stats []modelMemoryStats
+	models.Range(func(key, value any) bool {
+		switch model := value.(type) {
+		case map[string]float64:
+			stats = append(stats, modelMemoryStats{
+				languageOfModelKey(key),
+				ngramLength,
+				0,
+				len(model),
+				estimateMapBytes(model, 8),
+			})
+		case quantizedLanguageModel[uint8]:
+			stats = append(stats, modelMemoryStats{
+				languageOfModelKey(key),
+				ngramLength,
+				8,
+				model.ngramCount(),
+				model.estimatedBytes(),
+			})
+		case quantizedLanguageModel[uint16]:
+			stats = append(stats, modelMemoryStats{
+				languageOfModelKey(key),
+				ngramLength,
+				16,
+				model.ngramCount(),
+				model.estimatedBytes(),
+			})
+		}
+		return true
+	})
+	return stats
+}
+
+func languageOfModelKey(key any) Language {
+	if language, ok := key.(Language); ok {
+		return language
+	}
+	return key.(languageModelKey).language
+}
+
+// estimateMapBytes estimates the number of bytes occupied by a map with
+// string keys, including the bytes of the keys themselves. It assumes that
+// the map has been created with its final size as capacity hint, so that
+// its tables have been allocated at once.
+func estimateMapBytes[T any](model map[string]T, valueBytes int) uint64 {
+	slotBytes := alignBytes(stringHeaderBytes + valueBytes)
+	groupBytes := mapControlWordBytes + mapGroupSlots*slotBytes
+
+	tableBytes := groupBytes
+	if len(model) > mapGroupSlots {
+		targetCapacity := len(model) * mapGroupSlots / mapMaxAvgGroupLoad
+		tableCount := nextPowerOfTwo((targetCapacity + mapMaxTableCapacity - 1) / mapMaxTableCapacity)
+		tableCapacity := nextPowerOfTwo(targetCapacity / tableCount)
+		if tableCapacity < mapGroupSlots {
+			tableCapacity = mapGroupSlots
+		}
+		tableBytes = tableCount * (pointerBytes + mapTableBytes + tableCapacity/mapGroupSlots*groupBytes)
+	}
+
+	keyBytes := 0
+	for key := range model {
+		keyBytes += alignBytes(len(key))
+	}
+
+	return uint64(mapHeaderBytes + tableBytes + keyBytes)
+}
+
+func nextPowerOfTwo(n int) int {
+	result := 1
+	for result < n {
+		result *= 2
+	}
+	return result
+}
+
+func alignBytes(bytes int) int {
+	return (bytes + memoryAlignment - 1) / memoryAlignment * memoryAlignment
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestEstimateMapBytes(t *testing.T) {
+	assert.Equal(t, uint64(48+200+16), estimateMapBytes(map[string]float64{"ab": 0.1, "c": 0.2}, 8))
+	assert.Equal(t, uint64(48+200+16), estimateMapBytes(map[string]uint8{"ab": 1, "c": 2}, 1))
+
+	largeModel := make(map[string]float64)
+	for i := 0; i < 1000; i++ {
+		largeModel[fmt.Sprintf("%03d", i)] = 0.1
+	}
+	assert.Equal(t, uint64(48+2*(8+32+128*200)+1000*8), estimateMapBytes(largeModel, 8))
+}
+
+func TestEstimateMapBytesAgainstHeap(t *testing.T) {
+	// The first load fills caches which must not be measured.
+	var warmUpModels sync.Map
+	_, err := loadLanguageModels(&warmUpModels, English, 3, false, nil)
+	assert.NoError(t, err)
+
+	var models sync.Map
+	before := readHeapAllocBytes()
+	model, err := loadLanguageModels(&models, English, 3, false, nil)
+	after := readHeapAllocBytes()
+	assert.NoError(t, err)
+
+	heapBytes := float64(after) - float64(before)
+	assert.InEpsilon(t, heapBytes, float64(estimateMapBytes(model, 8)), 0.1)
+
+	runtime.KeepAlive(model)
+}
+
+func readHeapAllocBytes() uint64 {
+	// Collecting twice makes the measurement more stable.
+	runtime.GC()
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+func TestLanguageModelMemoryUsage(t *testing.T) {
+	model, err := loadLanguageModels(&trigramModels, English, 3, false, nil)
+	assert.NoError(t, err)
+	quantizedModel, err := loadQuantizedLanguageModels[uint8](&trigramModels, English, 3, 8, false, nil)
+	assert.NoError(t, err)
+
+	var fullPrecisionStats, quantizedStats ModelMemoryStats
+	for _, stats := range LanguageModelMemoryUsage() {
+		if stats.Language() == English && stats.NgramLength() == 3 {
+			switch stats.QuantizationBits() {
+			case 0:
+				fullPrecisionStats = stats
+			case 8:
+				quantizedStats = stats
+			}
+		}
+	}
+
+	assert.NotNil(t, fullPrecisionStats)
+	assert.Equal(t, len(model), fullPrecisionStats.NgramCount())
+	assert.Equal(t, estimateMapBytes(model, 8), fullPrecisionStats.EstimatedBytes())
+
+	assert.NotNil(t, quantizedStats)
+	assert.Equal(t, quantizedModel.ngramCount(), quantizedStats.NgramCount())
+	assert.Less(t, quantizedStats.EstimatedBytes(), fullPrecisionStats.EstimatedBytes())
+}
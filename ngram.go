@@ -18,6 +18,7 @@ package lingua
 
 import (
 	"fmt"
+	"golang.org/x/exp/slices"
 	"unicode/utf8"
 )
 
@@ -50,6 +51,74 @@ func getNgramNameByLength(ngramLength int) string {
 	}
 }
 
+// NgramLengths holds the lengths of the ngrams whose language models are
+// evaluated for short and long texts. They are configured with
+// LanguageDetectorBuilder.WithNgramLengths. By default, all ngram lengths from
+// 1 to 5 are used for texts of less than 120 characters and trigrams only for
+// longer texts.
+type NgramLengths struct {
+	// Short are the ngram lengths used for texts of less than
+	// LongTextThreshold characters.
+	Short []int
+
+	// Long are the ngram lengths used for texts of at least
+	// LongTextThreshold characters.
+	Long []int
+
+	// LongTextThreshold is the number of characters from which on
+	// a text is considered to be long.
+	LongTextThreshold int
+}
+
+var defaultNgramLengths = NgramLengths{Short: allNgramLengths(), Long: []int{3}, LongTextThreshold: 120}
+
+// forCharacterCount returns the ngram lengths to use for a text
+// of the given number of characters.
+func (lengths NgramLengths) forCharacterCount(characterCount int) []int {
+	if characterCount >= lengths.LongTextThreshold {
+		return lengths.Long
+	}
+	return lengths.Short
+}
+
+// all returns the ngram lengths used for short or long texts in ascending order.
+func (lengths NgramLengths) all() []int {
+	var ngramLengths []int
+	for _, ngramLength := range allNgramLengths() {
+		if slices.Contains(lengths.Short, ngramLength) || slices.Contains(lengths.Long, ngramLength) {
+			ngramLengths = append(ngramLengths, ngramLength)
+		}
+	}
+	return ngramLengths
+}
+
+func validateNgramLengths(lengths NgramLengths) {
+	for _, ngramLengths := range [][]int{lengths.Short, lengths.Long} {
+		if len(ngramLengths) == 0 {
+			panic("Ngram lengths for short and long texts must not be empty")
+		}
+		for i, ngramLength := range ngramLengths {
+			if ngramLength < 1 || ngramLength > maxNgramLength {
+				panic(fmt.Sprintf("Ngram length %v is not in range 1..%v", ngramLength, maxNgramLength))
+			}
+			if slices.Contains(ngramLengths[:i], ngramLength) {
+				panic(fmt.Sprintf("Ngram length %v must not be given more than once", ngramLength))
+			}
+		}
+	}
+	if lengths.LongTextThreshold < 1 {
+		panic("Long text threshold must be greater than 0")
+	}
+}
+
+func allNgramLengths() []int {
+	ngramLengths := make([]int, maxNgramLength)
+	for i := range ngramLengths {
+		ngramLengths[i] = i + 1
+	}
+	return ngramLengths
+}
+
 func (n ngram) rangeOfLowerOrderNgrams() []ngram {
 	var ngrams []ngram
 	chars := []rune(n.value)
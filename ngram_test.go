@@ -34,3 +34,46 @@ func TestRangeOfLowerOrderNgrams(t *testing.T) {
 		},
 		n.rangeOfLowerOrderNgrams())
 }
+
+func TestNgramLengths(t *testing.T) {
+	lengths := NgramLengths{Short: []int{1, 2, 3}, Long: []int{3, 4}, LongTextThreshold: 60}
+
+	assert.Equal(t, []int{1, 2, 3}, lengths.forCharacterCount(59))
+	assert.Equal(t, []int{3, 4}, lengths.forCharacterCount(60))
+	assert.Equal(t, []int{1, 2, 3, 4}, lengths.all())
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, defaultNgramLengths.forCharacterCount(119))
+	assert.Equal(t, []int{3}, defaultNgramLengths.forCharacterCount(120))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, defaultNgramLengths.all())
+}
+
+func TestLanguageDetector_WithNgramLengths(t *testing.T) {
+	text := "Ein kurzer Satz auf Deutsch"
+
+	trigramDetector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		WithNgramLengths(NgramLengths{Short: []int{3}, Long: []int{3}, LongTextThreshold: 120}).
+		Build()
+
+	lowAccuracyDetector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		WithLowAccuracyMode().
+		Build()
+
+	expectedValues := lowAccuracyDetector.ComputeLanguageConfidenceValues(text)
+	actualValues := trigramDetector.ComputeLanguageConfidenceValues(text)
+
+	assert.Len(t, actualValues, len(expectedValues))
+	for i, expectedValue := range expectedValues {
+		assert.Equal(t, expectedValue.Language(), actualValues[i].Language())
+		assert.InDelta(t, expectedValue.Value(), actualValues[i].Value(), 1e-9)
+	}
+
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		WithNgramLengths(NgramLengths{Short: []int{4, 2, 1}, Long: []int{3}, LongTextThreshold: 10}).
+		Build().(languageDetector)
+
+	assert.Equal(t, []int{1, 2, 4}, detector.ngramLengths.Short)
+	assert.Equal(t, []int{1, 2, 3, 4}, detector.ngramLengths.all())
+}
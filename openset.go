@@ -0,0 +1,106 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import "math"
+
+// OpenSetThresholds holds the thresholds below which a text is considered
+// to be written in none of the languages of the detector if open-set
+// detection has been enabled with LanguageDetectorBuilder.WithOpenSetDetection.
+//
+// Both of them are computed from the trigrams of the text. As the probability
+// of a trigram in a language model is the probability of its last character
+// given the two preceding ones, the average logarithmized probability of the
+// trigrams is the log-likelihood per character of the text.
+type OpenSetThresholds struct {
+	// LogLikelihood is the minimum log-likelihood per character which
+	// the text must reach in at least one of the languages. Trigrams which
+	// are not contained in a language model are replaced with their bigrams
+	// and unigrams as usual.
+	LogLikelihood float64
+
+	// Coverage is the minimum share of the text's trigrams which must be
+	// contained in the language model of at least one of the languages.
+	Coverage float64
+}
+
+// isUnsupportedLanguage reports whether the given words fit none of the
+// given languages according to the open-set thresholds of this detector.
+// Texts without any trigrams are never rejected.
+func (detector languageDetector) isUnsupportedLanguage(words []string, languages []Language) bool {
+	if detector.openSetThresholds == nil {
+		return false
+	}
+	coverage, logLikelihood, hasTrigrams := detector.computeOpenSetScores(words, languages)
+	if !hasTrigrams {
+		return false
+	}
+	return coverage < detector.openSetThresholds.Coverage ||
+		logLikelihood < detector.openSetThresholds.LogLikelihood
+}
+
+// computeOpenSetScores returns the share of the trigrams of the given words
+// which are contained in the language model of at least one of the given
+// languages and the highest log-likelihood per character among them. The
+// last return value is false if the words do not contain any trigrams. In
+// low accuracy mode, trigrams are not replaced with their bigrams and
+// unigrams, so that no further language models need to be loaded.
+func (detector languageDetector) computeOpenSetScores(words []string, languages []Language) (float64, float64, bool) {
+	ngramModel := newTestDataLanguageModel(words, 3)
+	if len(ngramModel.ngrams) == 0 {
+		return 0, 0, false
+	}
+
+	coveredNgramCount := 0
+	logLikelihoods := make(map[Language]float64, len(languages))
+	matchedNgramCounts := make(map[Language]int, len(languages))
+	failedLanguages := make(map[Language]bool)
+
+	for _, ngrams := range ngramModel.ngrams {
+		if detector.isLowAccuracyModeEnabled {
+			ngrams = ngrams[:1]
+		}
+		isCovered := false
+		for _, language := range languages {
+			if failedLanguages[language] {
+				continue
+			}
+			for i, ngrm := range ngrams {
+				probability, err := detector.lookUpNgramProbability(language, ngrm)
+				if err != nil {
+					failedLanguages[language] = true
+					break
+				}
+				if probability > 0 {
+					logLikelihoods[language] += math.Log(probability)
+					matchedNgramCounts[language]++
+					isCovered = isCovered || i == 0
+					break
+				}
+			}
+		}
+		if isCovered {
+			coveredNgramCount++
+		}
+	}
+
+	maximumLogLikelihood := math.Inf(-1)
+	for language, logLikelihood := range logLikelihoods {
+		maximumLogLikelihood = math.Max(maximumLogLikelihood, logLikelihood/float64(matchedNgramCounts[language]))
+	}
+	return float64(coveredNgramCount) / float64(len(ngramModel.ngrams)), maximumLogLikelihood, true
+}
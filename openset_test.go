@@ -0,0 +1,89 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComputeOpenSetScores(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		Build().(languageDetector)
+
+	coverage, logLikelihood, hasTrigrams := detector.computeOpenSetScores(
+		splitTextIntoWords("The quick brown fox jumps over the lazy dog"),
+		detector.languages,
+	)
+	assert.True(t, hasTrigrams)
+	assert.Equal(t, 1.0, coverage)
+	assert.Greater(t, logLikelihood, -3.0)
+
+	coverage, logLikelihood, hasTrigrams = detector.computeOpenSetScores(
+		splitTextIntoWords("Wszystko bylo dobrze"),
+		detector.languages,
+	)
+	assert.True(t, hasTrigrams)
+	assert.Equal(t, 1.0, coverage)
+	assert.Less(t, logLikelihood, -3.0)
+
+	coverage, _, hasTrigrams = detector.computeOpenSetScores(
+		splitTextIntoWords("xqz qxz"),
+		detector.languages,
+	)
+	assert.True(t, hasTrigrams)
+	assert.Equal(t, 0.0, coverage)
+
+	_, _, hasTrigrams = detector.computeOpenSetScores(splitTextIntoWords("ok"), detector.languages)
+	assert.False(t, hasTrigrams)
+}
+
+func TestOpenSetDetection(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		Build().(languageDetector)
+
+	language, reason := detector.detectLanguageOf("Wszystko bylo dobrze")
+	assert.Equal(t, German, language)
+	assert.Equal(t, ReasonNgramModels, reason)
+
+	detector = NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		WithOpenSetDetection(OpenSetThresholds{LogLikelihood: -3, Coverage: 0.9}).
+		Build().(languageDetector)
+
+	language, reason = detector.detectLanguageOf("Wszystko bylo dobrze")
+	assert.Equal(t, Unknown, language)
+	assert.Equal(t, ReasonUnsupportedLanguage, reason)
+
+	for _, value := range detector.ComputeLanguageConfidenceValues("Wszystko bylo dobrze") {
+		assert.Equal(t, 0.0, value.Value())
+	}
+
+	language, reason = detector.detectLanguageOf("The quick brown fox jumps over the lazy dog")
+	assert.Equal(t, English, language)
+	assert.Equal(t, ReasonNgramModels, reason)
+
+	// Texts without any trigrams and texts identified by the rule engine are not affected.
+	_, reason = detector.detectLanguageOf("ok")
+	assert.NotEqual(t, ReasonUnsupportedLanguage, reason)
+
+	language, reason = detector.detectLanguageOf("straße")
+	assert.Equal(t, German, language)
+	assert.Equal(t, ReasonRules, reason)
+}
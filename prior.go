@@ -0,0 +1,65 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"fmt"
+	"golang.org/x/exp/slices"
+	"math"
+)
+
+// languagePriorTolerance allows language priors which have been computed
+// as shares of some total to exceed 1.0 slightly due to rounding errors.
+const languagePriorTolerance = 1e-9
+
+// computeLogPriors validates the given language priors and returns their
+// logarithms for all of the given languages. The languages without a prior
+// share the probability which the given priors leave equally.
+func computeLogPriors(languages []Language, priors map[Language]float64) map[Language]float64 {
+	sum := 0.0
+	for language, prior := range priors {
+		if !slices.Contains(languages, language) {
+			panic(fmt.Sprintf("Language %v of the language priors is not among the languages of the detector", language))
+		}
+		if prior <= 0 || prior > 1 {
+			panic("Language priors must lie in between 0.0 exclusively and 1.0 inclusively")
+		}
+		sum += prior
+	}
+	if sum > 1+languagePriorTolerance {
+		panic("Language priors must not sum up to more than 1.0")
+	}
+
+	remainingLanguageCount := len(languages) - len(priors)
+	remainingPrior := 0.0
+	if remainingLanguageCount > 0 {
+		remainingPrior = (1 - sum) / float64(remainingLanguageCount)
+		if remainingPrior <= languagePriorTolerance {
+			panic("Language priors must leave some probability for the languages without prior")
+		}
+	}
+
+	logPriors := make(map[Language]float64, len(languages))
+	for _, language := range languages {
+		prior, exists := priors[language]
+		if !exists {
+			prior = remainingPrior
+		}
+		logPriors[language] = math.Log(prior)
+	}
+	return logPriors
+}
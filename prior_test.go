@@ -0,0 +1,76 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestComputeLogPriors(t *testing.T) {
+	logPriors := computeLogPriors(
+		[]Language{English, French, German, Spanish},
+		map[Language]float64{English: 0.7, Spanish: 0.2},
+	)
+	assert.Len(t, logPriors, 4)
+	assert.InDelta(t, math.Log(0.7), logPriors[English], 1e-9)
+	assert.InDelta(t, math.Log(0.2), logPriors[Spanish], 1e-9)
+	assert.InDelta(t, math.Log(0.05), logPriors[French], 1e-9)
+	assert.InDelta(t, math.Log(0.05), logPriors[German], 1e-9)
+
+	logPriors = computeLogPriors(
+		[]Language{English, German},
+		map[Language]float64{English: 0.3, German: 0.1},
+	)
+	assert.InDelta(t, math.Log(0.3), logPriors[English], 1e-9)
+	assert.InDelta(t, math.Log(0.1), logPriors[German], 1e-9)
+}
+
+func TestLanguagePriors(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German, Spanish).
+		Build()
+
+	language, _ := detector.DetectLanguageOf("bank")
+	assert.Equal(t, German, language)
+
+	detectorWithPriors := NewLanguageDetectorBuilder().
+		FromLanguages(English, German, Spanish).
+		WithLanguagePriors(map[Language]float64{English: 0.7, Spanish: 0.2}).
+		Build()
+
+	language, _ = detectorWithPriors.DetectLanguageOf("bank")
+	assert.Equal(t, English, language)
+
+	values := detector.ComputeLanguageConfidenceValues("bank")
+	valuesWithPriors := detectorWithPriors.ComputeLanguageConfidenceValues("bank")
+	assert.Equal(t, English, valuesWithPriors[0].Language())
+	assert.Greater(
+		t,
+		valuesWithPriors[0].Value(),
+		detector.ComputeLanguageConfidence("bank", English),
+	)
+	assert.Less(
+		t,
+		detectorWithPriors.ComputeLanguageConfidence("bank", German),
+		values[0].Value(),
+	)
+
+	// Languages identified by the rule engine are not affected.
+	assert.Equal(t, 1.0, detectorWithPriors.ComputeLanguageConfidence("straße", German))
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/pemistahl/lingua-go/serialization"
+	"math"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+type quantizationLevel interface {
+	uint8 | uint16
+}
+
+// languageModelKey is used as key for storing quantized language models
+// and language models merged with those of script variants in the same
+// caches as the full precision models which are stored by their language only.
+type languageModelKey struct {
+	language           Language
+	bits               int
+	withScriptVariants bool
+}
+
+// languageModelKeyOf returns the key under which the language models of the
+// given language are cached with the given quantization bits, which are 0
+// for full precision models.
+func languageModelKeyOf(language Language, bits int, withScriptVariants bool) any {
+	if bits == 0 && !withScriptVariants {
+		return language
+	}
+	return languageModelKey{language, bits, withScriptVariants}
+}
+
+// quantizedLanguageModel stores the ngrams of a language model sorted and
+// concatenated in a single string, so that neither a map nor a string header
+// per ngram is needed. The ngram at position i spans the bytes from offsets[i]
+// to offsets[i+1] and has the probability levels[codes[i]]. The table of levels
+// only contains the levels which are actually used by the model.
+type quantizedLanguageModel[T quantizationLevel] struct {
+	ngrams  string
+	offsets []uint32
+	codes   []T
+	levels  []float64
+}
+
+type quantizedNgram struct {
+	ngram string
+	code  int
+}
+
+// newQuantizedLanguageModel keeps the probabilities of the given model exactly
+// if it has no more distinct probabilities than can be encoded with the given
+// bits. Otherwise, each logarithmized probability is rounded to the nearest of
+// 2^bits evenly spaced levels between the smallest and the largest
+// logarithmized probability of the model.
+func newQuantizedLanguageModel[T quantizationLevel](
+	model *serialization.SerializableLanguageModel,
+	bits int,
+) quantizedLanguageModel[T] {
+	quantizedProbabilities := quantizeProbabilities(model.NgramSets, bits)
+	var levels []float64
+	codesByProbability := make(map[float64]int)
+	for _, probability := range quantizedProbabilities {
+		if _, exists := codesByProbability[probability]; !exists {
+			codesByProbability[probability] = len(levels)
+			levels = append(levels, probability)
+		}
+	}
+
+	ngrams := make([]quantizedNgram, 0, model.TotalNgrams)
+	byteCount := 0
+	for i, ngramSet := range model.NgramSets {
+		code := codesByProbability[quantizedProbabilities[i]]
+		for _, ngrm := range ngramSet.Ngrams {
+			ngrams = append(ngrams, quantizedNgram{ngrm, code})
+			byteCount += len(ngrm)
+		}
+	}
+	sort.Slice(ngrams, func(i, j int) bool {
+		return ngrams[i].ngram < ngrams[j].ngram
+	})
+
+	var builder strings.Builder
+	builder.Grow(byteCount)
+	offsets := make([]uint32, 0, len(ngrams)+1)
+	codes := make([]T, 0, len(ngrams))
+	for _, ngrm := range ngrams {
+		offsets = append(offsets, uint32(builder.Len()))
+		builder.WriteString(ngrm.ngram)
+		codes = append(codes, T(ngrm.code))
+	}
+	offsets = append(offsets, uint32(builder.Len()))
+
+	return quantizedLanguageModel[T]{builder.String(), offsets, codes, levels}
+}
+
+// quantizeProbabilities returns the probability of each ngram set rounded to
+// the nearest level. If there are not more ngram sets than levels, the exact
+// probabilities are returned, so that no precision is lost.
+func quantizeProbabilities(ngramSets []*serialization.SerializableNgramSet, bits int) []float64 {
+	probabilities := make([]float64, len(ngramSets))
+	if len(ngramSets) <= 1<<bits {
+		for i, ngramSet := range ngramSets {
+			probabilities[i] = ngramSet.Probability
+		}
+		return probabilities
+	}
+
+	minLogProbability := math.Inf(1)
+	maxLogProbability := math.Inf(-1)
+	for _, ngramSet := range ngramSets {
+		logProbability := math.Log(ngramSet.Probability)
+		minLogProbability = math.Min(minLogProbability, logProbability)
+		maxLogProbability = math.Max(maxLogProbability, logProbability)
+	}
+	step := (maxLogProbability - minLogProbability) / float64(int(1)<<bits-1)
+
+	for i, ngramSet := range ngramSets {
+		level := 0.0
+		if step > 0 {
+			level = math.Round((math.Log(ngramSet.Probability) - minLogProbability) / step)
+		}
+		probabilities[i] = math.Exp(minLogProbability + level*step)
+	}
+	return probabilities
+}
+
+func (model quantizedLanguageModel[T]) ngramCount() int {
+	return len(model.codes)
+}
+
+func (model quantizedLanguageModel[T]) ngramAt(i int) string {
+	return model.ngrams[model.offsets[i]:model.offsets[i+1]]
+}
+
+func (model quantizedLanguageModel[T]) probability(ngram string) float64 {
+	count := model.ngramCount()
+	i := sort.Search(count, func(i int) bool {
+		return model.ngramAt(i) >= ngram
+	})
+	if i < count && model.ngramAt(i) == ngram {
+		return model.levels[model.codes[i]]
+	}
+	return 0
+}
+
+func (model quantizedLanguageModel[T]) forEachNgram(callback func(ngram string, probability float64)) {
+	for i, code := range model.codes {
+		callback(model.ngramAt(i), model.levels[code])
+	}
+}
+
+// estimatedBytes returns the number of bytes occupied by the model's arrays.
+func (model quantizedLanguageModel[T]) estimatedBytes() uint64 {
+	var code T
+	return uint64(len(model.ngrams)) +
+		uint64(len(model.offsets))*4 +
+		uint64(len(model.codes))*uint64(unsafe.Sizeof(code)) +
+		uint64(len(model.levels))*8
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"fmt"
+	"github.com/pemistahl/lingua-go/serialization"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+var modelToQuantize = serialization.SerializableLanguageModel{
+	Language:    serialization.SerializableLanguage_ENGLISH,
+	NgramLength: 1,
+	TotalNgrams: 5,
+	NgramSets: []*serialization.SerializableNgramSet{
+		{Probability: 0.001, Ngrams: []string{"q", "z"}},
+		{Probability: 0.01, Ngrams: []string{"b"}},
+		{Probability: 0.1, Ngrams: []string{"t"}},
+		{Probability: 0.5, Ngrams: []string{"e"}},
+	},
+}
+
+func TestNewQuantizedLanguageModel(t *testing.T) {
+	model8bit := newQuantizedLanguageModel[uint8](&modelToQuantize, 8)
+	model16bit := newQuantizedLanguageModel[uint16](&modelToQuantize, 16)
+
+	assert.Equal(t, "beqtz", model8bit.ngrams)
+	assert.Equal(t, []uint32{0, 1, 2, 3, 4, 5}, model8bit.offsets)
+	assert.Len(t, model8bit.levels, 4)
+	assert.Len(t, model16bit.levels, 4)
+	assert.Equal(t, 5, model8bit.ngramCount())
+	assert.Equal(t, 5, model16bit.ngramCount())
+
+	for _, ngramSet := range modelToQuantize.NgramSets {
+		for _, ngrm := range ngramSet.Ngrams {
+			message := fmt.Sprintf("unexpected probability for ngram '%s'", ngrm)
+			assert.Equal(t, ngramSet.Probability, model8bit.probability(ngrm), message)
+			assert.Equal(t, ngramSet.Probability, model16bit.probability(ngrm), message)
+		}
+	}
+
+	assert.Equal(t, 0.0, model8bit.probability("x"))
+	assert.Equal(t, 0.0, model16bit.probability("x"))
+	assert.Equal(t, 0.0, model8bit.probability(""))
+	assert.Equal(t, 0.0, quantizedLanguageModel[uint8]{}.probability("e"))
+}
+
+func TestNewQuantizedLanguageModelWithMoreProbabilitiesThanLevels(t *testing.T) {
+	model := serialization.SerializableLanguageModel{
+		Language:    serialization.SerializableLanguage_ENGLISH,
+		NgramLength: 2,
+		TotalNgrams: 300,
+	}
+	for i := 0; i < 300; i++ {
+		model.NgramSets = append(model.NgramSets, &serialization.SerializableNgramSet{
+			Probability: 0.5 / float64(i+1),
+			Ngrams:      []string{fmt.Sprintf("%c%c", rune('a'+i/26), rune('a'+i%26))},
+		})
+	}
+
+	quantizedModel := newQuantizedLanguageModel[uint8](&model, 8)
+
+	assert.LessOrEqual(t, len(quantizedModel.levels), 256)
+	assert.Equal(t, 300, quantizedModel.ngramCount())
+
+	logRange := math.Log(0.5) - math.Log(0.5/300)
+	previousNgram := ""
+	quantizedModel.forEachNgram(func(ngram string, probability float64) {
+		assert.Less(t, previousNgram, ngram)
+		previousNgram = ngram
+	})
+
+	for _, ngramSet := range model.NgramSets {
+		ngrm := ngramSet.Ngrams[0]
+		assert.InDelta(
+			t,
+			math.Log(ngramSet.Probability),
+			math.Log(quantizedModel.probability(ngrm)),
+			logRange/255/2+1e-9,
+			fmt.Sprintf("unexpected quantization error for ngram '%s'", ngrm),
+		)
+	}
+}
+
+func TestQuantizedLanguageDetection(t *testing.T) {
+	for _, bits := range []int{8, 16} {
+		detector := newLanguageDetector([]Language{English, German}, 0.0, false, false)
+		detector.quantizationBits = bits
+
+		language, exists := detector.DetectLanguageOf("languages are awesome")
+		assert.Equal(t, English, language, fmt.Sprintf("wrong language for %v bits", bits))
+		assert.True(t, exists)
+
+		language, exists = detector.DetectLanguageOf("Sprachen sind großartig")
+		assert.Equal(t, German, language, fmt.Sprintf("wrong language for %v bits", bits))
+		assert.True(t, exists)
+	}
+}
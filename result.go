@@ -26,6 +26,13 @@ type DetectionResult interface {
 	EndIndex() int
 	// Language returns the language being part of this DetectionResult.
 	Language() Language
+	// Variant returns the variant of the language being part of this
+	// DetectionResult, or UnknownLanguageVariant if it has none.
+	Variant() LanguageVariant
+	// Script returns the script which most characters of the identified
+	// single-language substring are written in, or UnknownScript if neither
+	// the substring nor the language has any script.
+	Script() Script
 }
 
 type detectionResult struct {
@@ -33,10 +40,12 @@ type detectionResult struct {
 	endIndex   int
 	wordCount  int
 	language   Language
+	variant    LanguageVariant
+	script     Script
 }
 
 func newDetectionResult(startIndex, endIndex, wordCount int, language Language) detectionResult {
-	return detectionResult{startIndex, endIndex, wordCount, language}
+	return detectionResult{startIndex, endIndex, wordCount, language, UnknownLanguageVariant, UnknownScript}
 }
 
 func (slice detectionResult) StartIndex() int {
@@ -50,3 +59,11 @@ func (slice detectionResult) EndIndex() int {
 func (slice detectionResult) Language() Language {
 	return slice.language
 }
+
+func (slice detectionResult) Variant() LanguageVariant {
+	return slice.variant
+}
+
+func (slice detectionResult) Script() Script {
+	return slice.script
+}
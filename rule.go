@@ -0,0 +1,200 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import "golang.org/x/exp/slices"
+
+// Rule is the interface describing a detection rule which is applied to the
+// words of the input text before the language models are consulted.
+//
+// Custom rules allow to inject domain knowledge into the language detection,
+// such as vocabularies which are specific to a certain product. They are
+// registered with LanguageDetectorBuilder.WithRules and run in addition to
+// the built-in rules which are based on alphabets and characters unique to
+// certain languages.
+type Rule interface {
+	// Apply receives the lowercased words of the input text and the
+	// candidate languages which have not been excluded by any of the
+	// previous rules. It must not modify either of them.
+	//
+	// If the rule identifies a language unambiguously, this language is to be
+	// returned. It must be one of the candidate languages, otherwise it is
+	// ignored. Language detection stops then and the language receives a
+	// confidence value of 1.0.
+	//
+	// Otherwise, Unknown is to be returned together with the candidate
+	// languages that remain possible according to this rule. Returning nil or
+	// an empty slice leaves the candidate languages unchanged.
+	Apply(words []string, candidates []Language) (Language, []Language)
+}
+
+// RuleFunc is an adapter which allows to use an ordinary function as a Rule.
+type RuleFunc func(words []string, candidates []Language) (Language, []Language)
+
+// Apply calls f(words, candidates).
+func (f RuleFunc) Apply(words []string, candidates []Language) (Language, []Language) {
+	return f(words, candidates)
+}
+
+// RuleOrder is the type used for enumerating the positions at which custom
+// rules can be run relative to the built-in rules.
+type RuleOrder int
+
+const (
+	// BeforeBuiltInRules runs custom rules before the built-in rules.
+	// The built-in rules then only consider the remaining candidate languages.
+	BeforeBuiltInRules RuleOrder = iota
+
+	// AfterBuiltInRules runs custom rules after the built-in rules
+	// on the candidate languages which the built-in rules have left over.
+	AfterBuiltInRules
+)
+
+// RuleEvidenceWeights holds the weights with which the results of the
+// built-in rules are combined with the language models if soft rule evidence
+// has been enabled with LanguageDetectorBuilder.WithSoftRuleEvidence.
+//
+// The weights are added to the logarithmized probabilities of the languages.
+// If unigrams are among the ngram lengths evaluated for a text, which by
+// default is the case for texts shorter than NgramLengths.LongTextThreshold
+// outside of low accuracy mode, these are averaged over the characters of the
+// text, so a weight of 1.0 multiplies the probability of a language by e for
+// every character. Otherwise, they are summed up, so the weights matter less
+// the longer the text is.
+type RuleEvidenceWeights struct {
+	// Detection is the weight of the language which the built-in rules
+	// identify by alphabets and characters unique to it.
+	Detection float64
+
+	// Filter is the weight of each language which remains after the built-in
+	// rules have filtered the languages by the characters of the input text.
+	Filter float64
+}
+
+// RuleWordShares holds the thresholds of the built-in rules which are
+// expressed as shares of the number of words in the input text. Both of
+// them are 0.5 by default.
+type RuleWordShares struct {
+	// Unknown is the share of words which do not point to any language
+	// from which on the built-in rules do not identify a language if these
+	// words outnumber the words pointing to any single language.
+	Unknown float64
+
+	// Characters is the share of words which must contain characters of a
+	// language so that the built-in rules keep only such languages. If
+	// function words are enabled, it is also the share of words which must
+	// be function words of a language so that only such languages are kept.
+	Characters float64
+}
+
+var defaultRuleWordShares = RuleWordShares{Unknown: 0.5, Characters: 0.5}
+
+// applyRules runs the custom rules registered to run before the built-in
+// rules, the built-in rules themselves including the optional function word
+// stage and the custom rules registered to run after them. It returns the
+// language identified unambiguously by any of them, or Unknown together with
+// the remaining candidate languages. If soft rule evidence is enabled, the
+// weights which the built-in rules have assigned to the candidate languages
+// are returned as well.
+func (detector languageDetector) applyRules(words []string) (Language, []Language, map[Language]float64) {
+	language, candidates := applyCustomRules(detector.rulesBeforeBuiltIns, words, detector.languages)
+	if language != Unknown {
+		return language, candidates, nil
+	}
+
+	detector.languages = candidates
+
+	var ruleEvidence map[Language]float64
+
+	if detector.ruleEvidenceWeights == nil {
+		if language = detector.detectLanguageWithRules(words); language != Unknown {
+			return language, candidates, nil
+		}
+		candidates = detector.filterLanguagesByRules(words)
+	} else {
+		language, candidates, ruleEvidence = detector.collectRuleEvidence(words)
+		if language != Unknown {
+			return language, candidates, nil
+		}
+	}
+
+	if len(candidates) == 1 {
+		return Unknown, candidates, ruleEvidence
+	}
+
+	candidates = detector.filterLanguagesByFunctionWords(words, candidates)
+
+	if len(candidates) == 1 {
+		return Unknown, candidates, ruleEvidence
+	}
+
+	language, candidates = applyCustomRules(detector.rulesAfterBuiltIns, words, candidates)
+	return language, candidates, ruleEvidence
+}
+
+// collectRuleEvidence runs the built-in rules in soft rule evidence mode.
+// Only the filtering by alphabets remains a hard decision. The language
+// identified by unique characters and the languages kept by the filtering
+// by characters receive weights instead. A language without language models
+// is still returned directly as it could not be detected otherwise.
+func (detector languageDetector) collectRuleEvidence(words []string) (Language, []Language, map[Language]float64) {
+	ruleEvidence := make(map[Language]float64)
+
+	if language := detector.detectLanguageWithRules(words); language != Unknown {
+		if !language.hasLanguageModels() {
+			return language, detector.languages, nil
+		}
+		ruleEvidence[language] += detector.ruleEvidenceWeights.Detection
+	}
+
+	candidates, isFiltered := detector.filterLanguagesByAlphabets(words)
+
+	if isFiltered {
+		for _, language := range detector.filterLanguagesByCharacters(words, candidates) {
+			ruleEvidence[language] += detector.ruleEvidenceWeights.Filter
+		}
+	}
+
+	return Unknown, candidates, ruleEvidence
+}
+
+func applyCustomRules(rules []Rule, words []string, candidates []Language) (Language, []Language) {
+	if len(rules) == 0 {
+		return Unknown, candidates
+	}
+
+	candidates = slices.Clone(candidates)
+	words = slices.Clone(words)
+
+	for _, rule := range rules {
+		language, remainingCandidates := rule.Apply(words, candidates)
+		if language != Unknown && slices.Contains(candidates, language) {
+			return language, candidates
+		}
+		var intersection []Language
+		for _, candidate := range remainingCandidates {
+			if slices.Contains(candidates, candidate) && !slices.Contains(intersection, candidate) {
+				intersection = append(intersection, candidate)
+			}
+		}
+		if len(intersection) > 0 {
+			candidates = intersection
+		}
+	}
+
+	return Unknown, candidates
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slices"
+	"testing"
+)
+
+func vocabularyRule(language Language, vocabulary ...string) Rule {
+	return RuleFunc(func(words []string, candidates []Language) (Language, []Language) {
+		for _, word := range words {
+			if slices.Contains(vocabulary, word) {
+				return language, nil
+			}
+		}
+		return Unknown, nil
+	})
+}
+
+func candidatesRule(languages ...Language) Rule {
+	return RuleFunc(func(words []string, candidates []Language) (Language, []Language) {
+		return Unknown, languages
+	})
+}
+
+func TestApplyCustomRules(t *testing.T) {
+	testCases := []struct {
+		rules              []Rule
+		expectedLanguage   Language
+		expectedCandidates []Language
+	}{
+		{
+			[]Rule{vocabularyRule(German, "kaffeeautomat")},
+			German,
+			[]Language{English, French, German},
+		},
+		{
+			[]Rule{vocabularyRule(Spanish, "kaffeeautomat")},
+			Unknown,
+			[]Language{English, French, German},
+		},
+		{
+			[]Rule{candidatesRule(German, English, German, Spanish)},
+			Unknown,
+			[]Language{German, English},
+		},
+		{
+			[]Rule{candidatesRule(), candidatesRule(Spanish)},
+			Unknown,
+			[]Language{English, French, German},
+		},
+		{
+			[]Rule{candidatesRule(English, German), vocabularyRule(French, "kaffeeautomat")},
+			Unknown,
+			[]Language{English, German},
+		},
+	}
+	for _, testCase := range testCases {
+		language, candidates := applyCustomRules(
+			testCase.rules,
+			[]string{"der", "kaffeeautomat"},
+			[]Language{English, French, German},
+		)
+		assert.Equal(t, testCase.expectedLanguage, language)
+		assert.Equal(t, testCase.expectedCandidates, candidates)
+	}
+}
+
+func TestRulesBeforeBuiltInRules(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German).
+		WithRules(BeforeBuiltInRules, vocabularyRule(German, "kaffeeautomat")).
+		Build()
+
+	language, exists := detector.DetectLanguageOf("My kaffeeautomat is broken")
+	assert.Equal(t, German, language)
+	assert.True(t, exists)
+	assert.Equal(t, 1.0, detector.ComputeLanguageConfidence("My kaffeeautomat is broken", German))
+
+	detector = NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German).
+		WithRules(BeforeBuiltInRules, candidatesRule(English, French)).
+		Build()
+
+	assert.Equal(t, 0.0, detector.ComputeLanguageConfidence("straße", German))
+}
+
+func TestRulesAfterBuiltInRules(t *testing.T) {
+	var receivedCandidates []Language
+	recordingRule := RuleFunc(func(words []string, candidates []Language) (Language, []Language) {
+		receivedCandidates = slices.Clone(candidates)
+		return Unknown, []Language{German}
+	})
+
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German, Greek).
+		WithRules(AfterBuiltInRules, recordingRule).
+		Build()
+
+	language, exists := detector.DetectLanguageOf("languages are awesome")
+
+	assert.ElementsMatch(t, []Language{English, German}, receivedCandidates)
+	assert.Equal(t, German, language)
+	assert.True(t, exists)
+}
+
+func TestSoftRuleEvidence(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German, Greek).
+		WithSoftRuleEvidence(RuleEvidenceWeights{Detection: 2, Filter: 1}).
+		Build().(languageDetector)
+
+	language, candidates, ruleEvidence := detector.applyRules([]string{"straße"})
+	assert.Equal(t, Unknown, language)
+	assert.ElementsMatch(t, []Language{English, French, German}, candidates)
+	assert.Equal(t, map[Language]float64{German: 2}, ruleEvidence)
+
+	language, candidates, ruleEvidence = detector.applyRules([]string{"über"})
+	assert.Equal(t, Unknown, language)
+	assert.ElementsMatch(t, []Language{English, French, German}, candidates)
+	assert.Equal(t, map[Language]float64{German: 1}, ruleEvidence)
+
+	_, reason := detector.computeLanguageConfidenceValues("straße")
+	assert.Equal(t, ReasonNgramModels, reason)
+	assert.Less(t, detector.ComputeLanguageConfidence("straße", German), 1.0)
+	assert.Greater(t, detector.ComputeLanguageConfidence("straße", English), 0.0)
+
+	_, reason = detector.computeLanguageConfidenceValues("καλημέρα")
+	assert.Equal(t, ReasonRuleFilter, reason)
+}
+
+func TestSoftRuleEvidenceKeepsLanguagesWithoutLanguageModels(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(Amharic, English, Greek).
+		WithSoftRuleEvidence(RuleEvidenceWeights{Detection: 1, Filter: 1}).
+		Build().(languageDetector)
+
+	// The word mixes alphabets, so the languages cannot be filtered by them.
+	language, _, ruleEvidence := detector.applyRules([]string{"ሰላምa"})
+	assert.Equal(t, Amharic, language)
+	assert.Nil(t, ruleEvidence)
+}
+
+func TestRuleWordShares(t *testing.T) {
+	words := []string{"straße", "closed"}
+
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		Build().(languageDetector)
+
+	assert.Equal(t, Unknown, detector.detectLanguageWithRules(words))
+
+	detector = NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		WithRuleWordShares(RuleWordShares{Unknown: 0.6, Characters: 0.5}).
+		Build().(languageDetector)
+
+	assert.Equal(t, German, detector.detectLanguageWithRules(words))
+
+	words = []string{"über", "the", "road"}
+
+	detector = NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		Build().(languageDetector)
+
+	assert.ElementsMatch(t, []Language{English, German}, detector.filterLanguagesByRules(words))
+
+	detector = NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		WithRuleWordShares(RuleWordShares{Unknown: 0.5, Characters: 0.3}).
+		Build().(languageDetector)
+
+	assert.Equal(t, []Language{German}, detector.filterLanguagesByRules(words))
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import "sort"
+
+// Script is the type used for enumerating the writing systems
+// which the supported languages are written in.
+//
+//go:generate stringer -type=Script
+type Script int
+
+const (
+	ArabicScript     = Script(arabic)
+	ArmenianScript   = Script(armenian)
+	BengaliScript    = Script(bengali)
+	CyrillicScript   = Script(cyrillic)
+	DevanagariScript = Script(devanagari)
+	EthiopicScript   = Script(ethiopic)
+	GeorgianScript   = Script(georgian)
+	GreekScript      = Script(greek)
+	GujaratiScript   = Script(gujarati)
+	GurmukhiScript   = Script(gurmukhi)
+	HanScript        = Script(han)
+	HangulScript     = Script(hangul)
+	HebrewScript     = Script(hebrew)
+	HiraganaScript   = Script(hiragana)
+	KannadaScript    = Script(kannada)
+	KatakanaScript   = Script(katakana)
+	KhmerScript      = Script(khmer)
+	LaoScript        = Script(lao)
+	LatinScript      = Script(latin)
+	MalayalamScript  = Script(malayalam)
+	MyanmarScript    = Script(myanmar)
+	OriyaScript      = Script(oriya)
+	SinhalaScript    = Script(sinhala)
+	TamilScript      = Script(tamil)
+	TeluguScript     = Script(telugu)
+	ThaanaScript     = Script(thaana)
+	ThaiScript       = Script(thai)
+	TibetanScript    = Script(tibetan)
+
+	// UnknownScript is used if a text does not contain any
+	// characters of the scripts above.
+	UnknownScript = Script(tibetan + 1)
+)
+
+// ScriptShare is the interface describing the share of a script's characters
+// within a text. It is computed by DetectScriptsOf.
+type ScriptShare interface {
+	// Script returns the script being part of this ScriptShare.
+	Script() Script
+
+	// Share returns the share of the script's characters which lies between 0.0 and 1.0.
+	Share() float64
+}
+
+type scriptShare struct {
+	script Script
+	share  float64
+}
+
+func (s scriptShare) Script() Script {
+	return s.script
+}
+
+func (s scriptShare) Share() float64 {
+	return s.share
+}
+
+// DetectScriptsOf determines the scripts which the characters of the given
+// text are written in. The shares of the scripts are computed relative to all
+// characters belonging to one of the scripts, so that whitespace, digits,
+// punctuation and characters of other scripts are ignored. The scripts are
+// sorted by their share in descending order. If none of the characters belong
+// to any of the scripts, an empty slice is returned.
+func DetectScriptsOf(text string) []ScriptShare {
+	scriptCounts := make(map[alphabet]int)
+	totalCount := 0
+
+	for _, chr := range text {
+		if alphabet, exists := alphabetOf(chr); exists {
+			scriptCounts[alphabet]++
+			totalCount++
+		}
+	}
+
+	shares := make([]ScriptShare, 0, len(scriptCounts))
+	for alphabet, count := range scriptCounts {
+		shares = append(shares, scriptShare{alphabet.script(), float64(count) / float64(totalCount)})
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		first, second := shares[i], shares[j]
+		if first.Share() == second.Share() {
+			return first.Script() < second.Script()
+		}
+		return first.Share() > second.Share()
+	})
+
+	return shares
+}
+
+// Scripts returns the scripts which the language is written in.
+func (language Language) Scripts() []Script {
+	alphabets := language.alphabets()
+	scripts := make([]Script, len(alphabets))
+	for i, alphabet := range alphabets {
+		scripts[i] = alphabet.script()
+	}
+	return scripts
+}
+
+func (alphabet alphabet) script() Script {
+	return Script(alphabet)
+}
+
+// dominantScriptOf returns the script which most characters of the given
+// text are written in. If there is none, the first script of the language
+// is returned, or UnknownScript if the language has no script either.
+func dominantScriptOf(language Language, text string) Script {
+	if shares := DetectScriptsOf(text); len(shares) > 0 {
+		return shares[0].Script()
+	}
+	if scripts := language.Scripts(); len(scripts) > 0 {
+		return scripts[0]
+	}
+	return UnknownScript
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDetectScriptsOf(t *testing.T) {
+	testCases := []struct {
+		text           string
+		expectedShares []ScriptShare
+	}{
+		{"", []ScriptShare{}},
+		{"123 !?", []ScriptShare{}},
+		{"Hello, world!", []ScriptShare{scriptShare{LatinScript, 1.0}}},
+		{"Hi 世界", []ScriptShare{scriptShare{HanScript, 0.5}, scriptShare{LatinScript, 0.5}}},
+		{"Мир, hi", []ScriptShare{scriptShare{CyrillicScript, 0.6}, scriptShare{LatinScript, 0.4}}},
+		{"カタカナ ひらがな 漢字", []ScriptShare{
+			scriptShare{HiraganaScript, 0.4},
+			scriptShare{KatakanaScript, 0.4},
+			scriptShare{HanScript, 0.2},
+		}},
+	}
+	for _, testCase := range testCases {
+		shares := DetectScriptsOf(testCase.text)
+		assert.Len(t, shares, len(testCase.expectedShares), "text: %s", testCase.text)
+		for i, expectedShare := range testCase.expectedShares {
+			assert.Equal(t, expectedShare.Script(), shares[i].Script(), "text: %s", testCase.text)
+			assert.InDelta(t, expectedShare.Share(), shares[i].Share(), 0.00001, "text: %s", testCase.text)
+		}
+	}
+}
+
+func TestLanguageScripts(t *testing.T) {
+	assert.Equal(t, []Script{LatinScript}, English.Scripts())
+	assert.Equal(t, []Script{CyrillicScript}, Russian.Scripts())
+	assert.Equal(t, []Script{HiraganaScript, KatakanaScript, HanScript}, Japanese.Scripts())
+	assert.Equal(t, "LatinScript", LatinScript.String())
+}
+
+func TestDominantScriptOf(t *testing.T) {
+	assert.Equal(t, CyrillicScript, dominantScriptOf(Serbian, "Београд i Novi"))
+	assert.Equal(t, CyrillicScript, dominantScriptOf(Russian, "2005"))
+	assert.Equal(t, UnknownScript, dominantScriptOf(Unknown, "2005"))
+	assert.Equal(t, "UnknownScript", UnknownScript.String())
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import "math"
+
+// Uncertainty is the interface describing measures of how uncertain the
+// confidence values of a text are. It is computed by
+// LanguageDetector.ComputeUncertainty.
+//
+// If no language has received a confidence value greater than 0.0,
+// all measures are 0.0.
+type Uncertainty interface {
+	// ConfidenceValues returns the confidence values which the measures
+	// have been computed from, as returned by
+	// LanguageDetector.ComputeLanguageConfidenceValues.
+	ConfidenceValues() []ConfidenceValue
+
+	// Entropy returns the entropy of the confidence values in nats. It is
+	// 0.0 if a single language has received all confidence and ln(n) if
+	// n languages have received the same confidence values.
+	Entropy() float64
+
+	// Margin returns the difference between the confidence values of the
+	// most likely and the second most likely language.
+	Margin() float64
+
+	// Ratio returns the confidence value of the second most likely language
+	// divided by the one of the most likely language. It lies between 0.0
+	// for an unambiguous result and 1.0 for a tie.
+	Ratio() float64
+
+	// EffectiveLanguageCount returns the exponential of the entropy, which is
+	// the number of equally likely languages that would result in the same
+	// entropy. It is 1.0 if a single language has received all confidence.
+	EffectiveLanguageCount() float64
+}
+
+type uncertainty struct {
+	confidenceValues       []ConfidenceValue
+	entropy                float64
+	margin                 float64
+	ratio                  float64
+	effectiveLanguageCount float64
+}
+
+// newUncertainty computes the uncertainty measures of the given confidence
+// values, which are expected to be sorted in descending order.
+func newUncertainty(confidenceValues []ConfidenceValue) Uncertainty {
+	result := uncertainty{confidenceValues: confidenceValues}
+	if len(confidenceValues) == 0 || confidenceValues[0].Value() == 0 {
+		return result
+	}
+
+	for _, confidenceValue := range confidenceValues {
+		if value := confidenceValue.Value(); value > 0 {
+			result.entropy -= value * math.Log(value)
+		}
+	}
+	result.effectiveLanguageCount = math.Exp(result.entropy)
+
+	mostLikelyValue := confidenceValues[0].Value()
+	secondMostLikelyValue := 0.0
+	if len(confidenceValues) > 1 {
+		secondMostLikelyValue = confidenceValues[1].Value()
+	}
+	result.margin = mostLikelyValue - secondMostLikelyValue
+	result.ratio = secondMostLikelyValue / mostLikelyValue
+
+	return result
+}
+
+func (u uncertainty) ConfidenceValues() []ConfidenceValue {
+	return u.confidenceValues
+}
+
+func (u uncertainty) Entropy() float64 {
+	return u.entropy
+}
+
+func (u uncertainty) Margin() float64 {
+	return u.margin
+}
+
+func (u uncertainty) Ratio() float64 {
+	return u.ratio
+}
+
+func (u uncertainty) EffectiveLanguageCount() float64 {
+	return u.effectiveLanguageCount
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestNewUncertainty(t *testing.T) {
+	testCases := []struct {
+		values                         []float64
+		expectedEntropy                float64
+		expectedMargin                 float64
+		expectedRatio                  float64
+		expectedEffectiveLanguageCount float64
+	}{
+		{[]float64{1, 0, 0}, 0, 1, 0, 1},
+		{[]float64{0.5, 0.5, 0}, math.Log(2), 0, 1, 2},
+		{[]float64{0.6, 0.3, 0.1}, -0.6*math.Log(0.6) - 0.3*math.Log(0.3) - 0.1*math.Log(0.1), 0.3, 0.5, 2.4546},
+		{[]float64{0, 0, 0}, 0, 0, 0, 0},
+	}
+	for _, testCase := range testCases {
+		confidenceValues := confidenceValuesOf(testCase.values...)
+		u := newUncertainty(confidenceValues)
+		assert.Equal(t, confidenceValues, u.ConfidenceValues())
+		assert.InDelta(t, testCase.expectedEntropy, u.Entropy(), 1e-9)
+		assert.InDelta(t, testCase.expectedMargin, u.Margin(), 1e-9)
+		assert.InDelta(t, testCase.expectedRatio, u.Ratio(), 1e-9)
+		assert.InDelta(t, testCase.expectedEffectiveLanguageCount, u.EffectiveLanguageCount(), 1e-3)
+	}
+}
+
+func TestComputeUncertainty(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German).
+		Build()
+
+	u := detector.ComputeUncertainty("bank")
+	for i, value := range detector.ComputeLanguageConfidenceValues("bank") {
+		assert.Equal(t, value.Language(), u.ConfidenceValues()[i].Language())
+		assert.InDelta(t, value.Value(), u.ConfidenceValues()[i].Value(), 1e-9)
+	}
+	assert.Equal(t, German, u.ConfidenceValues()[0].Language())
+	assert.InDelta(t, u.ConfidenceValues()[0].Value()-u.ConfidenceValues()[1].Value(), u.Margin(), 1e-9)
+	assert.Greater(t, u.EffectiveLanguageCount(), 1.0)
+	assert.Less(t, u.EffectiveLanguageCount(), 3.0)
+
+	u = detector.ComputeUncertainty("straße")
+	assert.Equal(t, 0.0, u.Entropy())
+	assert.Equal(t, 1.0, u.Margin())
+	assert.Equal(t, 1.0, u.EffectiveLanguageCount())
+}
@@ -0,0 +1,274 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"bufio"
+	"embed"
+	"golang.org/x/exp/slices"
+	"strings"
+	"sync"
+)
+
+// LanguageVariant is the type used for enumerating the variants of those
+// languages which are written in more than one way, such as Simplified
+// and Traditional Chinese or Serbian in Cyrillic and Latin script.
+//
+//go:generate stringer -type=LanguageVariant
+type LanguageVariant int
+
+const (
+	// SerbianCyrillic is Serbian written in Cyrillic script.
+	SerbianCyrillic LanguageVariant = iota
+
+	// SerbianLatin is Serbian written in Latin script.
+	SerbianLatin
+
+	// SimplifiedChinese is Chinese written with simplified characters.
+	SimplifiedChinese
+
+	// TraditionalChinese is Chinese written with traditional characters.
+	TraditionalChinese
+
+	// UnknownLanguageVariant denotes that a language has no variants
+	// or that its variant cannot be determined.
+	UnknownLanguageVariant
+)
+
+//go:embed language-variants
+var languageVariantFiles embed.FS
+
+var (
+	chineseVariantCharacters     map[rune]LanguageVariant
+	chineseVariantCharactersOnce sync.Once
+)
+
+// AllLanguageVariants returns a sorted slice of all currently supported language variants.
+func AllLanguageVariants() []LanguageVariant {
+	variants := make([]LanguageVariant, UnknownLanguageVariant)
+	for i := range variants {
+		variants[i] = LanguageVariant(i)
+	}
+	return variants
+}
+
+// GetLanguageVariantFromBcp47Tag returns the language variant for the given
+// BCP 47 language tag, such as zh-Hans. The tag is matched case-insensitively.
+func GetLanguageVariantFromBcp47Tag(tag string) LanguageVariant {
+	for _, variant := range AllLanguageVariants() {
+		if strings.EqualFold(variant.Bcp47Tag(), tag) {
+			return variant
+		}
+	}
+	return UnknownLanguageVariant
+}
+
+// Language returns the language which the variant belongs to.
+func (variant LanguageVariant) Language() Language {
+	switch variant {
+	case SerbianCyrillic, SerbianLatin:
+		return Serbian
+	case SimplifiedChinese, TraditionalChinese:
+		return Chinese
+	default:
+		return Unknown
+	}
+}
+
+// Bcp47Tag returns the BCP 47 language tag of the variant, such as zh-Hans,
+// or an empty string for UnknownLanguageVariant.
+func (variant LanguageVariant) Bcp47Tag() string {
+	switch variant {
+	case SerbianCyrillic:
+		return "sr-Cyrl"
+	case SerbianLatin:
+		return "sr-Latn"
+	case SimplifiedChinese:
+		return "zh-Hans"
+	case TraditionalChinese:
+		return "zh-Hant"
+	default:
+		return ""
+	}
+}
+
+// Variants returns the variants which the language is written in,
+// or an empty slice if the language has no variants.
+func (language Language) Variants() []LanguageVariant {
+	variants := make([]LanguageVariant, 0)
+	for _, variant := range AllLanguageVariants() {
+		if variant.Language() == language {
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
+
+// detectLanguageVariant determines the variant of the given text which has
+// been written in the given language. UnknownLanguageVariant is returned if
+// the language has no variants or if the text does not allow to tell them apart.
+func detectLanguageVariant(language Language, text string) LanguageVariant {
+	switch language {
+	case Chinese:
+		return detectChineseVariant(text)
+	case Serbian:
+		return detectScriptVariant(language, text)
+	default:
+		return UnknownLanguageVariant
+	}
+}
+
+// detectScriptVariant returns the variant of the given language which is
+// written in the script that most characters of the text belong to.
+func detectScriptVariant(language Language, text string) LanguageVariant {
+	shares := DetectScriptsOf(text)
+	if len(shares) == 0 {
+		return UnknownLanguageVariant
+	}
+	for _, variant := range language.Variants() {
+		if variant.script() == shares[0].Script() {
+			return variant
+		}
+	}
+	return UnknownLanguageVariant
+}
+
+// script returns the script which the variant is written in.
+func (variant LanguageVariant) script() Script {
+	switch variant {
+	case SerbianCyrillic:
+		return CyrillicScript
+	case SerbianLatin:
+		return LatinScript
+	case SimplifiedChinese, TraditionalChinese:
+		return HanScript
+	default:
+		return UnknownScript
+	}
+}
+
+// hasOwnLanguageModels reports whether the variant comes with language
+// models of its own. These are stored in a directory named after its
+// BCP 47 tag and are only loaded together with the language models of the
+// language itself, which are written in another script, if script variants
+// have been enabled with LanguageDetectorBuilder.WithScriptVariants.
+func (variant LanguageVariant) hasOwnLanguageModels() bool {
+	return variant == SerbianLatin
+}
+
+// sharedCharacters returns those characters of the variant's script which
+// are listed in charsToLanguagesMapping for other languages. They point to
+// the variant's language as well if script variants have been enabled.
+func (variant LanguageVariant) sharedCharacters() string {
+	if variant == SerbianLatin {
+		return "ĆćĐđČčŠšŽž"
+	}
+	return ""
+}
+
+// hasScriptVariantModels reports whether any variant of the language comes
+// with language models of its own. It does not allocate, as it is called
+// for every lookup of an ngram probability.
+func (language Language) hasScriptVariantModels() bool {
+	for variant := LanguageVariant(0); variant < UnknownLanguageVariant; variant++ {
+		if variant.hasOwnLanguageModels() && variant.Language() == language {
+			return true
+		}
+	}
+	return false
+}
+
+// scriptVariantModelDirectories returns the directories of the language
+// models of those variants of the language which come with models of their own.
+func (language Language) scriptVariantModelDirectories() []string {
+	var directories []string
+	for _, variant := range language.Variants() {
+		if variant.hasOwnLanguageModels() {
+			directories = append(directories, "language-models/"+variant.Bcp47Tag())
+		}
+	}
+	return directories
+}
+
+// usesScriptVariantModels reports whether the language models of the given
+// language are merged with those of its script variants for this detector.
+func (detector languageDetector) usesScriptVariantModels(language Language) bool {
+	return detector.isScriptVariantDetectionEnabled && language.hasScriptVariantModels()
+}
+
+// alphabetsOf returns the alphabets of the given language, extended by the
+// alphabets of its script variants with language models of their own if
+// script variants have been enabled for this detector.
+func (detector languageDetector) alphabetsOf(language Language) []alphabet {
+	alphabets := alphabetsOfLanguages[language]
+	if !detector.usesScriptVariantModels(language) {
+		return alphabets
+	}
+	alphabets = slices.Clone(alphabets)
+	for _, variant := range language.Variants() {
+		if variant.hasOwnLanguageModels() {
+			alphabets = append(alphabets, alphabet(variant.script()))
+		}
+	}
+	return alphabets
+}
+
+// detectChineseVariant counts the characters which are used either in
+// Simplified or in Traditional Chinese only, and returns the variant with
+// more of them.
+func detectChineseVariant(text string) LanguageVariant {
+	chineseVariantCharactersOnce.Do(func() {
+		chineseVariantCharacters = make(map[rune]LanguageVariant)
+		loadLanguageVariantCharacters(chineseVariantCharacters, SimplifiedChinese)
+		loadLanguageVariantCharacters(chineseVariantCharacters, TraditionalChinese)
+	})
+
+	variantCounts := make(map[LanguageVariant]int)
+	for _, chr := range text {
+		if variant, exists := chineseVariantCharacters[chr]; exists {
+			variantCounts[variant]++
+		}
+	}
+
+	simplifiedCount := variantCounts[SimplifiedChinese]
+	traditionalCount := variantCounts[TraditionalChinese]
+
+	if simplifiedCount > traditionalCount {
+		return SimplifiedChinese
+	}
+	if traditionalCount > simplifiedCount {
+		return TraditionalChinese
+	}
+	return UnknownLanguageVariant
+}
+
+// loadLanguageVariantCharacters reads the characters which are unique to
+// the given variant from the embedded file named after its BCP 47 tag.
+func loadLanguageVariantCharacters(characters map[rune]LanguageVariant, variant LanguageVariant) {
+	file, err := languageVariantFiles.Open("language-variants/" + variant.Bcp47Tag() + ".txt")
+	if err != nil {
+		panic(err.Error())
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		for _, chr := range strings.TrimSpace(scanner.Text()) {
+			characters[chr] = variant
+		}
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const (
+	simplifiedChineseText  = "人们必须面对遭受严重破坏的自然生态"
+	traditionalChineseText = "人們必須面對遭受嚴重破壞的自然生態"
+	serbianCyrillicText    = "На њиховом челу налазио се један од Бајазитових синова, а прикључио им се и кнез Стефан."
+	serbianLatinText       = "Na njihovom čelu nalazio se jedan od Bajazitovih sinova, a priključio im se i knez Stefan."
+)
+
+func TestGetLanguageVariantFromBcp47Tag(t *testing.T) {
+	assert.Equal(t, SimplifiedChinese, GetLanguageVariantFromBcp47Tag("zh-Hans"))
+	assert.Equal(t, TraditionalChinese, GetLanguageVariantFromBcp47Tag("ZH-HANT"))
+	assert.Equal(t, SerbianLatin, GetLanguageVariantFromBcp47Tag("sr-latn"))
+	assert.Equal(t, UnknownLanguageVariant, GetLanguageVariantFromBcp47Tag("zh"))
+	assert.Equal(t, UnknownLanguageVariant, GetLanguageVariantFromBcp47Tag(""))
+}
+
+func TestLanguageVariants(t *testing.T) {
+	assert.Equal(t, []LanguageVariant{SimplifiedChinese, TraditionalChinese}, Chinese.Variants())
+	assert.Equal(t, []LanguageVariant{SerbianCyrillic, SerbianLatin}, Serbian.Variants())
+	assert.Empty(t, English.Variants())
+	assert.Equal(t, Chinese, TraditionalChinese.Language())
+	assert.Equal(t, Unknown, UnknownLanguageVariant.Language())
+}
+
+func TestDetectChineseVariant(t *testing.T) {
+	testCases := []struct {
+		text            string
+		expectedVariant LanguageVariant
+	}{
+		{simplifiedChineseText, SimplifiedChinese},
+		{traditionalChineseText, TraditionalChinese},
+		{"我们", SimplifiedChinese},
+		{"我們", TraditionalChinese},
+		{"中文", UnknownLanguageVariant},
+		{"国們", UnknownLanguageVariant},
+		{"", UnknownLanguageVariant},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedVariant, detectChineseVariant(testCase.text), "text: %s", testCase.text)
+	}
+}
+
+func TestDetectLanguageVariantOf(t *testing.T) {
+	testCases := []struct {
+		text             string
+		expectedLanguage Language
+		expectedVariant  LanguageVariant
+	}{
+		{simplifiedChineseText, Chinese, SimplifiedChinese},
+		{traditionalChineseText, Chinese, TraditionalChinese},
+		{serbianCyrillicText, Serbian, SerbianCyrillic},
+		{"languages are awesome", English, UnknownLanguageVariant},
+	}
+	for _, testCase := range testCases {
+		language, variant, exists := detectorForAllLanguages.DetectLanguageVariantOf(testCase.text)
+		assert.Equal(t, testCase.expectedLanguage, language, "text: %s", testCase.text)
+		assert.Equal(t, testCase.expectedVariant, variant, "text: %s", testCase.text)
+		assert.True(t, exists)
+	}
+}
+
+func TestDetectMultipleLanguagesReportsLanguageVariant(t *testing.T) {
+	results := detectorForAllLanguages.DetectMultipleLanguagesOf(traditionalChineseText)
+
+	assert.Len(t, results, 1)
+	assert.Equal(t, Chinese, results[0].Language())
+	assert.Equal(t, TraditionalChinese, results[0].Variant())
+	assert.Equal(t, HanScript, results[0].Script())
+}
+
+func TestDetectScriptVariant(t *testing.T) {
+	assert.Equal(t, SerbianCyrillic, detectLanguageVariant(Serbian, serbianCyrillicText))
+	assert.Equal(t, SerbianLatin, detectLanguageVariant(Serbian, serbianLatinText))
+	assert.Equal(t, SerbianLatin, detectLanguageVariant(Serbian, "Beograd i Novi Sad, Ниш"))
+	assert.Equal(t, UnknownLanguageVariant, detectLanguageVariant(Serbian, "2005"))
+}
+
+func TestDetectMultipleLanguagesReportsScript(t *testing.T) {
+	results := detectorForAllLanguages.DetectMultipleLanguagesOf(serbianCyrillicText + " " + serbianLatinText)
+
+	assert.NotEmpty(t, results)
+	assert.Equal(t, CyrillicScript, results[0].Script())
+	assert.Equal(t, LatinScript, results[len(results)-1].Script())
+}
+
+func TestScriptVariantLanguageModelsAreMerged(t *testing.T) {
+	for _, withScriptVariants := range []bool{false, true} {
+		model, err := loadSerializableLanguageModel(Serbian, 1, withScriptVariants)
+		assert.NoError(t, err)
+
+		unigrams := make(map[string]struct{})
+		for _, ngramSet := range model.NgramSets {
+			for _, ngrm := range ngramSet.Ngrams {
+				unigrams[ngrm] = struct{}{}
+			}
+		}
+		assert.Contains(t, unigrams, "ш")
+		if withScriptVariants {
+			assert.Contains(t, unigrams, "š")
+		} else {
+			assert.NotContains(t, unigrams, "š")
+		}
+		assert.Equal(t, int(model.TotalNgrams), len(unigrams))
+	}
+	assert.Equal(t, []string{"language-models/sr-Latn"}, Serbian.scriptVariantModelDirectories())
+	assert.Empty(t, Croatian.scriptVariantModelDirectories())
+	assert.True(t, Serbian.hasScriptVariantModels())
+	assert.False(t, Chinese.hasScriptVariantModels())
+}
+
+func TestDetectLanguageWithScriptVariants(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(Bosnian, Croatian, Serbian).
+		Build()
+
+	language, _ := detector.DetectLanguageOf(serbianLatinText)
+	assert.NotEqual(t, Serbian, language)
+	assert.NotContains(t, detector.(languageDetector).filterLanguagesByRules([]string{"nađete"}), Serbian)
+	assert.NotContains(t, AllLanguagesWithLatinScript(), Serbian)
+
+	detectorWithScriptVariants := NewLanguageDetectorBuilder().
+		FromLanguages(Bosnian, Croatian, Serbian).
+		WithScriptVariants().
+		Build()
+
+	language, variant, _ := detectorWithScriptVariants.DetectLanguageVariantOf(serbianLatinText)
+	assert.Equal(t, Serbian, language)
+	assert.Equal(t, SerbianLatin, variant)
+	language, variant, _ = detectorWithScriptVariants.DetectLanguageVariantOf(serbianCyrillicText)
+	assert.Equal(t, Serbian, language)
+	assert.Equal(t, SerbianCyrillic, variant)
+	assert.ElementsMatch(
+		t,
+		[]Language{Bosnian, Croatian, Serbian},
+		detectorWithScriptVariants.(languageDetector).filterLanguagesByRules([]string{"nađete"}),
+	)
+
+	// The merged language models are cached separately from the Cyrillic ones.
+	_, exists := trigramModels.Load(languageModelKey{Serbian, 0, true})
+	assert.True(t, exists)
+	detectorWithScriptVariants.UnloadLanguageModels()
+	_, exists = trigramModels.Load(languageModelKey{Serbian, 0, true})
+	assert.False(t, exists)
+}
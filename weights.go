@@ -0,0 +1,347 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// ngramOrderWeightIterations is the maximum number of gradient descent
+// steps taken by FitNgramOrderWeights.
+const ngramOrderWeightIterations = 300
+
+// ngramOrderWeightRegularization is the strength of the penalty which keeps
+// the fitted weights close to 1.0, so that languages with few labeled texts
+// do not end up with extreme weights.
+const ngramOrderWeightRegularization = 0.01
+
+// NgramOrderWeights is the interface describing the weights with which the
+// summed up logarithmized probabilities of the different ngram lengths are
+// combined. Without weights, all ngram lengths contribute equally.
+//
+// Weights are fitted by FitNgramOrderWeights, written with WriteTo and read
+// again with ReadNgramOrderWeights. They are applied to a detector with
+// LanguageDetectorBuilder.WithNgramOrderWeights.
+type NgramOrderWeights interface {
+	// WriteTo writes the weights in JSON format to the given writer.
+	WriteTo(w io.Writer) (int64, error)
+
+	weightOf(language Language, ngramLength int) float64
+}
+
+type ngramOrderWeights struct {
+	weights         []float64
+	languageWeights map[Language][]float64
+}
+
+// serializableNgramOrderWeights holds either the weights shared by all
+// languages or the weights of each language, keyed by its ISO 639-3 code.
+// The weights are ordered by ngram length from unigrams to fivegrams.
+type serializableNgramOrderWeights struct {
+	Weights         []float64            `json:"weights,omitempty"`
+	LanguageWeights map[string][]float64 `json:"languageWeights,omitempty"`
+}
+
+// ngramOrderSample holds the normalized probabilities of each candidate
+// language of a labeled text for each ngram length, the offsets added to
+// them by rule evidence and language priors, and the index of the true
+// language among the candidates.
+type ngramOrderSample struct {
+	languages     []Language
+	ngramLengths  []int
+	probabilities [][]float64
+	offsets       []float64
+	trueIndex     int
+}
+
+// FitNgramOrderWeights fits the weights of the ngram lengths with
+// multinomial logistic regression on the given labeled texts. The summed up
+// logarithmized probabilities of each ngram length are the features, and the
+// likelihood of the true languages of the texts under the confidence values
+// resulting from the weighted sums is maximized. If perLanguage is true,
+// each language receives weights of its own, otherwise all languages share
+// the same weights. The detector must have been built by
+// LanguageDetectorBuilder, and the weights should only be applied to
+// detectors with the same configuration.
+//
+// Fitting starts from the equal weights of 1.0 and takes the texts into
+// account whose confidence values are computed by the language models.
+// Texts whose true language has not been found in the language models
+// are ignored, as no weights can change their result.
+//
+// An error is returned if the detector has not been built by
+// LanguageDetectorBuilder or if none of the texts can be used for fitting.
+func FitNgramOrderWeights(detector LanguageDetector, texts []LabeledText, perLanguage bool) (NgramOrderWeights, error) {
+	builtDetector, ok := detector.(languageDetector)
+	if !ok {
+		return nil, errors.New("detector has not been built by LanguageDetectorBuilder")
+	}
+
+	var samples []ngramOrderSample
+	for _, text := range texts {
+		_, scores, reason := builtDetector.computeNgramScores(text.Text)
+		if reason != ReasonNgramModels {
+			continue
+		}
+		sample := ngramOrderSample{ngramLengths: scores.ngramLengths, trueIndex: -1}
+		for _, language := range scores.filteredLanguages {
+			probabilities := scores.normalizedProbabilities(language)
+			if sumOfFloats(probabilities) == 0 {
+				continue
+			}
+			if language == text.Language {
+				sample.trueIndex = len(sample.languages)
+			}
+			sample.languages = append(sample.languages, language)
+			sample.probabilities = append(sample.probabilities, probabilities)
+			sample.offsets = append(
+				sample.offsets,
+				scores.ruleEvidence[language]+builtDetector.logLanguagePriors[language],
+			)
+		}
+		if sample.trueIndex >= 0 && len(sample.languages) > 1 {
+			samples = append(samples, sample)
+		}
+	}
+
+	if len(samples) == 0 {
+		return nil, errors.New("none of the texts can be used for fitting the weights")
+	}
+
+	languages := []Language{Unknown}
+	if perLanguage {
+		languages = builtDetector.languages
+	}
+	parameters := make(map[Language][]float64, len(languages))
+	for _, language := range languages {
+		parameters[language] = []float64{1, 1, 1, 1, 1}
+	}
+	fitNgramOrderWeights(samples, parameters, perLanguage)
+
+	if perLanguage {
+		return ngramOrderWeights{languageWeights: parameters}, nil
+	}
+	return ngramOrderWeights{weights: parameters[Unknown]}, nil
+}
+
+// ReadNgramOrderWeights reads NgramOrderWeights in JSON format as written by
+// NgramOrderWeights.WriteTo from the given reader.
+//
+// An error is returned if the content cannot be read or does not describe
+// valid weights.
+func ReadNgramOrderWeights(r io.Reader) (NgramOrderWeights, error) {
+	var serialized serializableNgramOrderWeights
+	if err := json.NewDecoder(r).Decode(&serialized); err != nil {
+		return nil, err
+	}
+
+	if (serialized.Weights == nil) == (serialized.LanguageWeights == nil) {
+		return nil, errors.New("ngram order weights must contain either shared weights or language weights")
+	}
+	if serialized.Weights != nil {
+		if err := validateNgramOrderWeights(serialized.Weights); err != nil {
+			return nil, err
+		}
+		return ngramOrderWeights{weights: serialized.Weights}, nil
+	}
+
+	languageWeights := make(map[Language][]float64, len(serialized.LanguageWeights))
+	for isoCode, weights := range serialized.LanguageWeights {
+		language := GetLanguageFromIsoCode639_3(GetIsoCode639_3FromValue(isoCode))
+		if language == Unknown {
+			return nil, fmt.Errorf("unknown ISO 639-3 code '%s'", isoCode)
+		}
+		if err := validateNgramOrderWeights(weights); err != nil {
+			return nil, fmt.Errorf("%s: %w", isoCode, err)
+		}
+		languageWeights[language] = weights
+	}
+	return ngramOrderWeights{languageWeights: languageWeights}, nil
+}
+
+func validateNgramOrderWeights(weights []float64) error {
+	if len(weights) != maxNgramLength {
+		return fmt.Errorf("ngram order weights must contain %d values", maxNgramLength)
+	}
+	for _, weight := range weights {
+		if weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return errors.New("ngram order weights must be finite and not negative")
+		}
+	}
+	return nil
+}
+
+func (w ngramOrderWeights) WriteTo(writer io.Writer) (int64, error) {
+	serialized := serializableNgramOrderWeights{Weights: w.weights}
+	if w.languageWeights != nil {
+		serialized.LanguageWeights = make(map[string][]float64, len(w.languageWeights))
+		for language, weights := range w.languageWeights {
+			serialized.LanguageWeights[strings.ToLower(language.IsoCode639_3().String())] = weights
+		}
+	}
+	content, err := json.MarshalIndent(serialized, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	return bytes.NewReader(append(content, '\n')).WriteTo(writer)
+}
+
+// weightOf returns the weight of the given ngram length for the given
+// language. Languages without weights of their own and ngram lengths
+// outside the range 1..5 keep the weight 1.0.
+func (w ngramOrderWeights) weightOf(language Language, ngramLength int) float64 {
+	weights := w.weights
+	if w.languageWeights != nil {
+		weights = w.languageWeights[language]
+	}
+	if ngramLength < 1 || ngramLength > len(weights) {
+		return 1
+	}
+	return weights[ngramLength-1]
+}
+
+// fitNgramOrderWeights minimizes the mean negative log-likelihood of the
+// true languages of the given samples, plus a penalty on the squared
+// distances of the weights to 1.0, by projected gradient descent with
+// backtracking line search. The weights are updated in place and kept
+// non-negative. If perLanguage is false, all languages share the weights
+// stored for Unknown.
+func fitNgramOrderWeights(samples []ngramOrderSample, parameters map[Language][]float64, perLanguage bool) {
+	weightsOf := func(language Language) []float64 {
+		if perLanguage {
+			return parameters[language]
+		}
+		return parameters[Unknown]
+	}
+
+	loss := func() float64 {
+		sum := 0.0
+		for _, sample := range samples {
+			scores := sample.scores(weightsOf)
+			sum += logSumExp(scores) - scores[sample.trueIndex]
+		}
+		penalty := 0.0
+		for _, weights := range parameters {
+			for _, weight := range weights {
+				penalty += (weight - 1) * (weight - 1)
+			}
+		}
+		return sum/float64(len(samples)) + ngramOrderWeightRegularization*penalty/2
+	}
+
+	gradients := make(map[Language][]float64, len(parameters))
+	for language := range parameters {
+		gradients[language] = make([]float64, maxNgramLength)
+	}
+
+	currentLoss := loss()
+	stepSize := 1.0
+
+	for iteration := 0; iteration < ngramOrderWeightIterations; iteration++ {
+		for language, weights := range parameters {
+			for i, weight := range weights {
+				gradients[language][i] = ngramOrderWeightRegularization * (weight - 1)
+			}
+		}
+		for _, sample := range samples {
+			scores := sample.scores(weightsOf)
+			normalizer := logSumExp(scores)
+			for j, language := range sample.languages {
+				share := math.Exp(scores[j] - normalizer)
+				if j == sample.trueIndex {
+					share--
+				}
+				key := Unknown
+				if perLanguage {
+					key = language
+				}
+				for k, ngramLength := range sample.ngramLengths {
+					gradients[key][ngramLength-1] += share * sample.probabilities[j][k] / float64(len(samples))
+				}
+			}
+		}
+
+		previousParameters := make(map[Language][]float64, len(parameters))
+		for language, weights := range parameters {
+			previousParameters[language] = append([]float64(nil), weights...)
+		}
+
+		improved := false
+		for ; stepSize > 1e-12; stepSize /= 2 {
+			for language, weights := range parameters {
+				for i := range weights {
+					weights[i] = math.Max(0, previousParameters[language][i]-stepSize*gradients[language][i])
+				}
+			}
+			if newLoss := loss(); newLoss < currentLoss {
+				improved = currentLoss-newLoss > 1e-9
+				currentLoss = newLoss
+				break
+			}
+		}
+		if stepSize <= 1e-12 {
+			for language, weights := range previousParameters {
+				copy(parameters[language], weights)
+			}
+			return
+		}
+		if !improved {
+			return
+		}
+		stepSize *= 2
+	}
+}
+
+// scores returns the weighted sums of the normalized probabilities
+// plus the offsets of all candidate languages of the sample.
+func (sample ngramOrderSample) scores(weightsOf func(Language) []float64) []float64 {
+	scores := make([]float64, len(sample.languages))
+	for j, language := range sample.languages {
+		weights := weightsOf(language)
+		scores[j] = sample.offsets[j]
+		for k, ngramLength := range sample.ngramLengths {
+			scores[j] += weights[ngramLength-1] * sample.probabilities[j][k]
+		}
+	}
+	return scores
+}
+
+func logSumExp(values []float64) float64 {
+	maximum := math.Inf(-1)
+	for _, value := range values {
+		maximum = math.Max(maximum, value)
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += math.Exp(value - maximum)
+	}
+	return maximum + math.Log(sum)
+}
+
+func sumOfFloats(values []float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright © 2021-present Peter M. Stahl pemistahl@gmail.com
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either expressed or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lingua
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFitNgramOrderWeights_Shared(t *testing.T) {
+	// Unigrams favor the wrong language, fivegrams the true one,
+	// so the sum of both results in a tie.
+	var samples []ngramOrderSample
+	for i := 0; i < 10; i++ {
+		samples = append(samples, ngramOrderSample{
+			languages:     []Language{English, German},
+			ngramLengths:  []int{1, 5},
+			probabilities: [][]float64{{-5, -2}, {-3, -4}},
+			offsets:       []float64{0, 0},
+			trueIndex:     0,
+		})
+	}
+	parameters := map[Language][]float64{Unknown: {1, 1, 1, 1, 1}}
+	fitNgramOrderWeights(samples, parameters, false)
+
+	weights := parameters[Unknown]
+	assert.Less(t, weights[0], 1.0)
+	assert.Greater(t, weights[4], 1.0)
+	assert.Equal(t, []float64{1, 1, 1}, weights[1:4])
+}
+
+func TestFitNgramOrderWeights_PerLanguage(t *testing.T) {
+	// German is always chosen wrongly because its trigram
+	// probabilities are too high compared to English.
+	var samples []ngramOrderSample
+	for i := 0; i < 10; i++ {
+		samples = append(samples, ngramOrderSample{
+			languages:     []Language{English, German},
+			ngramLengths:  []int{3},
+			probabilities: [][]float64{{-3}, {-2}},
+			offsets:       []float64{0, 0},
+			trueIndex:     0,
+		})
+	}
+	parameters := map[Language][]float64{English: {1, 1, 1, 1, 1}, German: {1, 1, 1, 1, 1}}
+	fitNgramOrderWeights(samples, parameters, true)
+
+	assert.Less(t, parameters[English][2], 1.0)
+	assert.Greater(t, parameters[German][2], 1.0)
+}
+
+func TestNgramOrderWeights_WeightOf(t *testing.T) {
+	shared := ngramOrderWeights{weights: []float64{0.5, 1, 1.5, 2, 2.5}}
+	assert.Equal(t, 0.5, shared.weightOf(French, 1))
+	assert.Equal(t, 2.5, shared.weightOf(English, 5))
+	assert.Equal(t, 1.0, shared.weightOf(English, 6))
+
+	perLanguage := ngramOrderWeights{languageWeights: map[Language][]float64{German: {0.5, 1, 1.5, 2, 2.5}}}
+	assert.Equal(t, 1.5, perLanguage.weightOf(German, 3))
+	assert.Equal(t, 1.0, perLanguage.weightOf(English, 3))
+}
+
+func TestWriteAndReadNgramOrderWeights(t *testing.T) {
+	for _, weights := range []ngramOrderWeights{
+		{weights: []float64{0.5, 1, 1.5, 2, 2.5}},
+		{languageWeights: map[Language][]float64{English: {1, 1, 1, 1, 1}, German: {0.5, 1, 1.5, 2, 2.5}}},
+	} {
+		var buffer bytes.Buffer
+		n, err := weights.WriteTo(&buffer)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(buffer.Len()), n)
+
+		readWeights, err := ReadNgramOrderWeights(&buffer)
+		assert.NoError(t, err)
+		assert.Equal(t, weights, readWeights)
+	}
+}
+
+func TestReadNgramOrderWeights_Errors(t *testing.T) {
+	testCases := []struct {
+		content         string
+		expectedMessage string
+	}{
+		{
+			`{}`,
+			"ngram order weights must contain either shared weights or language weights",
+		},
+		{
+			`{"weights": [1, 1, 1, 1, 1], "languageWeights": {"eng": [1, 1, 1, 1, 1]}}`,
+			"ngram order weights must contain either shared weights or language weights",
+		},
+		{
+			`{"weights": [1, 1, 1]}`,
+			"ngram order weights must contain 5 values",
+		},
+		{
+			`{"weights": [1, 1, -1, 1, 1]}`,
+			"ngram order weights must be finite and not negative",
+		},
+		{
+			`{"languageWeights": {"xyz": [1, 1, 1, 1, 1]}}`,
+			"unknown ISO 639-3 code 'xyz'",
+		},
+		{
+			`{"languageWeights": {"deu": [1, 1]}}`,
+			"deu: ngram order weights must contain 5 values",
+		},
+	}
+	for _, testCase := range testCases {
+		weights, err := ReadNgramOrderWeights(strings.NewReader(testCase.content))
+		assert.Nil(t, weights)
+		assert.EqualError(t, err, testCase.expectedMessage)
+	}
+}
+
+func TestFitNgramOrderWeights(t *testing.T) {
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, French, German).
+		Build()
+
+	texts := []LabeledText{
+		{"hello", English},
+		{"house", English},
+		{"haus", German},
+		{"maison", French},
+		{"der kleine Hund", German},
+		{"the little dog", English},
+		{"le petit chien", French},
+		{"straße", German},
+	}
+
+	for _, perLanguage := range []bool{false, true} {
+		weights, err := FitNgramOrderWeights(detector, texts, perLanguage)
+		assert.NoError(t, err)
+
+		weightedDetector := NewLanguageDetectorBuilder().
+			FromLanguages(English, French, German).
+			WithNgramOrderWeights(weights).
+			Build()
+
+		for _, text := range texts[4:] {
+			language, exists := weightedDetector.DetectLanguageOf(text.Text)
+			assert.True(t, exists)
+			assert.Equal(t, text.Language, language)
+		}
+	}
+
+	weights, err := FitNgramOrderWeights(detector, []LabeledText{{"straße", German}}, false)
+	assert.Nil(t, weights)
+	assert.EqualError(t, err, "none of the texts can be used for fitting the weights")
+}
+
+func TestNgramOrderWeights_EqualWeights(t *testing.T) {
+	text := "Das ist ein kurzer Satz"
+	equalWeights := ngramOrderWeights{weights: []float64{1, 1, 1, 1, 1}}
+
+	detector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		Build()
+	weightedDetector := NewLanguageDetectorBuilder().
+		FromLanguages(English, German).
+		WithNgramOrderWeights(equalWeights).
+		Build()
+
+	expectedValues := detector.ComputeLanguageConfidenceValues(text)
+	actualValues := weightedDetector.ComputeLanguageConfidenceValues(text)
+
+	for i, expectedValue := range expectedValues {
+		assert.Equal(t, expectedValue.Language(), actualValues[i].Language())
+		assert.InDelta(t, expectedValue.Value(), actualValues[i].Value(), 1e-9)
+	}
+}
@@ -173,7 +173,11 @@ func CreateAndWriteLanguageModelFiles(
 		return err
 	}
 
-	return nil
+	var zipFileNames []string
+	for _, ngramLength := range allNgramLengths() {
+		zipFileNames = append(zipFileNames, languageModelFileName(ngramLength))
+	}
+	return writeChecksums(outputDirectoryPath, zipFileNames)
 }
 
 // CreateAndWriteTestDataFiles creates test data files for accuracy report
@@ -243,19 +243,21 @@ func TestCreateAndWriteLanguageModelFiles(t *testing.T) {
 
 	files, _ := os.ReadDir(outputDirectoryPath)
 
-	assert.Equal(t, 5, len(files), "number of language model files is not correct")
+	assert.Equal(t, 6, len(files), "number of language model files is not correct")
 
-	unigramsFile := files[4]
+	unigramsFile := files[5]
 	bigramsFile := files[0]
-	trigramsFile := files[3]
-	quadrigramsFile := files[2]
-	fivegramsFile := files[1]
+	trigramsFile := files[4]
+	quadrigramsFile := files[3]
+	fivegramsFile := files[2]
+	checksumsFile := files[1]
 
 	assert.Equal(t, "unigrams.pb.bin.zip", unigramsFile.Name())
 	assert.Equal(t, "bigrams.pb.bin.zip", bigramsFile.Name())
 	assert.Equal(t, "trigrams.pb.bin.zip", trigramsFile.Name())
 	assert.Equal(t, "quadrigrams.pb.bin.zip", quadrigramsFile.Name())
 	assert.Equal(t, "fivegrams.pb.bin.zip", fivegramsFile.Name())
+	assert.Equal(t, "checksums.txt", checksumsFile.Name())
 
 	assertLanguageModelFileContent(t, outputDirectoryPath, unigramsFile.Name(), "unigrams.pb.bin", &expectedUnigramModel)
 	assertLanguageModelFileContent(t, outputDirectoryPath, bigramsFile.Name(), "bigrams.pb.bin", &expectedBigramModel)
@@ -263,6 +265,8 @@ func TestCreateAndWriteLanguageModelFiles(t *testing.T) {
 	assertLanguageModelFileContent(t, outputDirectoryPath, quadrigramsFile.Name(), "quadrigrams.pb.bin", &expectedQuadrigramModel)
 	assertLanguageModelFileContent(t, outputDirectoryPath, fivegramsFile.Name(), "fivegrams.pb.bin", &expectedFivegramModel)
 
+	assert.NoError(t, VerifyLanguageModelFiles(outputDirectoryPath))
+
 	cleanUp(inputFilePath, outputDirectoryPath)
 }
 